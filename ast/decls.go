@@ -23,7 +23,8 @@ type (
 		CommentGroup *CommentGroup
 		Binding      *Binding
 		Value        Node
-		Loc          token.Loc
+		Loc          token.Loc // `var` or `val` token.
+		IsVal        bool      // Declared with `val`, so it cannot be mutated.
 	}
 
 	ConstDecl struct {
@@ -43,17 +44,20 @@ type (
 	}
 
 	StructDecl struct {
-		Attrs *AttributeList
-		Name  *Ident
-		Body  *CurlyList
-		Loc   token.Loc // `struct` token.
+		Attrs        *AttributeList
+		CommentGroup *CommentGroup
+		Name         *Ident
+		Body         *CurlyList // Fields are [Binding] nodes.
+		Loc          token.Loc  // `struct` token.
 	}
 
 	EnumDecl struct {
-		Attrs *AttributeList
-		Name  *Ident
-		Body  *CurlyList
-		Loc   token.Loc // `struct` token.
+		Attrs        *AttributeList
+		CommentGroup *CommentGroup
+		Name         *Ident
+		Body         *CurlyList
+		MemberDocs   map[*Ident]*CommentGroup // Doc comments of the members.
+		Loc          token.Loc                // `enum` token.
 	}
 
 	TypeAliasDecl struct {
@@ -102,15 +106,18 @@ func (n *FuncDecl) Attributes() *AttributeList { return n.Attrs }
 func (n *StructDecl) Pos() token.Loc             { return n.Loc }
 func (n *StructDecl) LocEnd() token.Loc          { return n.Body.LocEnd() }
 func (n *StructDecl) Ident() *Ident              { return n.Name }
-func (n *StructDecl) Doc() string                { return "" }
+func (n *StructDecl) Doc() string                { return n.CommentGroup.Merged() }
 func (n *StructDecl) Attributes() *AttributeList { return n.Attrs }
 
 func (n *EnumDecl) Pos() token.Loc             { return n.Loc }
 func (n *EnumDecl) LocEnd() token.Loc          { return n.Body.LocEnd() }
 func (n *EnumDecl) Ident() *Ident              { return n.Name }
-func (n *EnumDecl) Doc() string                { return "" }
+func (n *EnumDecl) Doc() string                { return n.CommentGroup.Merged() }
 func (n *EnumDecl) Attributes() *AttributeList { return n.Attrs }
 
+// Returns the doc comment of the enum member.
+func (n *EnumDecl) MemberDoc(member *Ident) string { return n.MemberDocs[member].Merged() }
+
 func (n *TypeAliasDecl) Pos() token.Loc             { return n.Loc }
 func (n *TypeAliasDecl) LocEnd() token.Loc          { return n.Expr.LocEnd() }
 func (n *TypeAliasDecl) Ident() *Ident              { return n.Name }
@@ -0,0 +1,346 @@
+package ast
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// Node types by name.
+var nodeTypes = map[string]reflect.Type{}
+
+var (
+	nodeType       = reflect.TypeFor[Node]()
+	memberDocsType = reflect.TypeFor[map[*Ident]*CommentGroup]()
+)
+
+func init() {
+	for _, node := range []Node{
+		(*BadNode)(nil),
+		(*Empty)(nil),
+		(*Ident)(nil),
+		(*Literal)(nil),
+		(*Operator)(nil),
+		(*BindingWithValue)(nil),
+		(*Binding)(nil),
+		(*BuiltInCall)(nil),
+		(*Call)(nil),
+		(*Index)(nil),
+		(*ArrayType)(nil),
+		(*Signature)(nil),
+		(*MemberAccess)(nil),
+		(*SafeMemberAccess)(nil),
+		(*PrefixOp)(nil),
+		(*InfixOp)(nil),
+		(*PostfixOp)(nil),
+		(*ParenList)(nil),
+		(*CurlyList)(nil),
+		(*BracketList)(nil),
+		(*If)(nil),
+		(*Else)(nil),
+		(*Match)(nil),
+		(*MatchArm)(nil),
+		(*ModuleDecl)(nil),
+		(*VarDecl)(nil),
+		(*ConstDecl)(nil),
+		(*FuncDecl)(nil),
+		(*StructDecl)(nil),
+		(*EnumDecl)(nil),
+		(*TypeAliasDecl)(nil),
+		(*Comment)(nil),
+		(*CommentGroup)(nil),
+		(*List)(nil),
+		(*ExprList)(nil),
+		(*AttributeList)(nil),
+		(*While)(nil),
+		(*For)(nil),
+		(*Return)(nil),
+		(*Break)(nil),
+		(*Continue)(nil),
+		(*Import)(nil),
+	} {
+		t := reflect.TypeOf(node).Elem()
+		nodeTypes[t.Name()] = t
+	}
+}
+
+// Encodes the tree to JSON. Use [UnmarshalNode] to decode it.
+//
+// Each node is encoded as an object. The "Node" key contains the name
+// of the node type, e.g. "Ident", the other keys are the fields of the
+// node in the declaration order:
+//
+//	{"Node":"Ident","Name":"x","Start":{...},"End":{...}}
+//
+// Embedded nodes are encoded as fields named by their type. Missing
+// nodes are encoded as null. Doc comments of the enum members are
+// encoded as an object where the keys are the names of the members.
+func MarshalNode(node Node) ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	if err := encodeNode(buf, reflect.ValueOf(&node).Elem()); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Decodes the tree encoded by [MarshalNode].
+func UnmarshalNode(data []byte) (Node, error) {
+	v, err := decodeNode(data)
+	if err != nil || !v.IsValid() {
+		return nil, err
+	}
+
+	return v.Interface().(Node), nil
+}
+
+func encodeNode(buf *bytes.Buffer, v reflect.Value) error {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+
+	if !v.IsValid() || v.IsNil() {
+		buf.WriteString("null")
+		return nil
+	}
+
+	elem := v.Elem()
+	name := elem.Type().Name()
+
+	if nodeTypes[name] != elem.Type() {
+		return fmt.Errorf("unknown node type '%s'", elem.Type())
+	}
+
+	buf.WriteString(`{"Node":`)
+	encodeString(buf, name)
+
+	for i := 0; i < elem.NumField(); i++ {
+		field := elem.Type().Field(i)
+
+		if !field.IsExported() {
+			continue
+		}
+
+		buf.WriteByte(',')
+		encodeString(buf, field.Name)
+		buf.WriteByte(':')
+
+		if err := encodeValue(buf, elem.Field(i)); err != nil {
+			return err
+		}
+	}
+
+	buf.WriteByte('}')
+	return nil
+}
+
+func encodeValue(buf *bytes.Buffer, v reflect.Value) error {
+	switch t := v.Type(); {
+	case t == memberDocsType:
+		return encodeMemberDocs(buf, v.Interface().(map[*Ident]*CommentGroup))
+
+	case t.Implements(nodeType):
+		return encodeNode(buf, v)
+
+	case t.Kind() == reflect.Slice && t.Elem().Implements(nodeType):
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+
+		buf.WriteByte('[')
+
+		for i := 0; i < v.Len(); i++ {
+			if i != 0 {
+				buf.WriteByte(',')
+			}
+
+			if err := encodeNode(buf, v.Index(i)); err != nil {
+				return err
+			}
+		}
+
+		buf.WriteByte(']')
+		return nil
+
+	default:
+		data, err := json.Marshal(v.Interface())
+		if err != nil {
+			return err
+		}
+
+		buf.Write(data)
+		return nil
+	}
+}
+
+// Members are encoded in the source order, so the output is stable.
+func encodeMemberDocs(buf *bytes.Buffer, docs map[*Ident]*CommentGroup) error {
+	if docs == nil {
+		buf.WriteString("null")
+		return nil
+	}
+
+	members := make([]*Ident, 0, len(docs))
+
+	for member := range docs {
+		members = append(members, member)
+	}
+
+	slices.SortFunc(members, func(a, b *Ident) int {
+		return int(a.Start.Offset) - int(b.Start.Offset)
+	})
+
+	buf.WriteByte('{')
+
+	for i, member := range members {
+		if i != 0 {
+			buf.WriteByte(',')
+		}
+
+		encodeString(buf, member.Name)
+		buf.WriteByte(':')
+
+		if err := encodeNode(buf, reflect.ValueOf(docs[member])); err != nil {
+			return err
+		}
+	}
+
+	buf.WriteByte('}')
+	return nil
+}
+
+func encodeString(buf *bytes.Buffer, s string) {
+	data, _ := json.Marshal(s)
+	buf.Write(data)
+}
+
+func decodeNode(data []byte) (reflect.Value, error) {
+	if isNull(data) {
+		return reflect.Value{}, nil
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return reflect.Value{}, err
+	}
+
+	name := ""
+	if err := json.Unmarshal(fields["Node"], &name); err != nil {
+		return reflect.Value{}, fmt.Errorf("expected node type: %w", err)
+	}
+
+	t := nodeTypes[name]
+	if t == nil {
+		return reflect.Value{}, fmt.Errorf("unknown node type '%s'", name)
+	}
+
+	node := reflect.New(t)
+	elem := node.Elem()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		raw, ok := fields[field.Name]
+
+		if !field.IsExported() || !ok || isNull(raw) {
+			continue
+		}
+
+		err := error(nil)
+
+		if field.Type == memberDocsType {
+			err = decodeMemberDocs(raw, elem)
+		} else {
+			err = decodeValue(raw, elem.Field(i))
+		}
+
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("%s.%s: %w", name, field.Name, err)
+		}
+	}
+
+	return node, nil
+}
+
+func decodeValue(data []byte, v reflect.Value) error {
+	switch t := v.Type(); {
+	case t.Implements(nodeType):
+		node, err := decodeNode(data)
+		if err != nil || !node.IsValid() {
+			return err
+		}
+
+		if !node.Type().AssignableTo(t) {
+			return fmt.Errorf("unexpected node '%s'", node.Elem().Type().Name())
+		}
+
+		v.Set(node)
+		return nil
+
+	case t.Kind() == reflect.Slice && t.Elem().Implements(nodeType):
+		elems := []json.RawMessage{}
+		if err := json.Unmarshal(data, &elems); err != nil {
+			return err
+		}
+
+		slice := reflect.MakeSlice(t, len(elems), len(elems))
+
+		for i, elem := range elems {
+			if err := decodeValue(elem, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+
+		v.Set(slice)
+		return nil
+
+	default:
+		return json.Unmarshal(data, v.Addr().Interface())
+	}
+}
+
+// The members are resolved by name in the body of the enum, so the
+// body must be decoded before.
+func decodeMemberDocs(data []byte, enum reflect.Value) error {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	body, _ := enum.FieldByName("Body").Interface().(*CurlyList)
+	docs := map[*Ident]*CommentGroup{}
+
+	for name, raw := range fields {
+		member := (*Ident)(nil)
+
+		if body != nil && body.List != nil {
+			for _, node := range body.Nodes {
+				if ident, _ := node.(*Ident); ident != nil && ident.Name == name {
+					member = ident
+					break
+				}
+			}
+		}
+
+		if member == nil {
+			return fmt.Errorf("unknown enum member '%s'", name)
+		}
+
+		doc := (*CommentGroup)(nil)
+		if err := decodeValue(raw, reflect.ValueOf(&doc).Elem()); err != nil {
+			return err
+		}
+
+		docs[member] = doc
+	}
+
+	enum.FieldByName("MemberDocs").Set(reflect.ValueOf(docs))
+	return nil
+}
+
+func isNull(data []byte) bool {
+	return len(data) == 0 || string(bytes.TrimSpace(data)) == "null"
+}
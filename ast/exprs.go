@@ -34,9 +34,10 @@ type (
 
 	// Represents `name Type`.
 	Binding struct {
-		Attrs *AttributeList
-		Name  *Ident
-		Type  Node
+		Attrs        *AttributeList
+		CommentGroup *CommentGroup // Doc comment of the struct field.
+		Name         *Ident
+		Type         Node
 	}
 
 	// Represents `name Type = value`.
@@ -147,6 +148,19 @@ type (
 		Body Node      // Can be either [*If] or [*CurlyList].
 		Loc  token.Loc // `else` token.
 	}
+
+	// Represents `match x { a, b => y; _ => z }`.
+	Match struct {
+		X    Node
+		Body *CurlyList // Contains only [*MatchArm] nodes.
+		Loc  token.Loc  // `match` token.
+	}
+
+	// Represents `a, b => x`. The `_` pattern matches any value.
+	MatchArm struct {
+		Patterns *ExprList
+		X        Node
+	}
 )
 
 func (n *BadNode) Pos() token.Loc    { return n.Loc }
@@ -179,6 +193,7 @@ func (n *Binding) LocEnd() token.Loc {
 	}
 	return n.Name.LocEnd()
 }
+func (n *Binding) Doc() string { return n.CommentGroup.Merged() }
 
 func (n *BuiltInCall) Pos() token.Loc    { return n.Loc }
 func (n *BuiltInCall) LocEnd() token.Loc { return n.Args.LocEnd() }
@@ -198,7 +213,12 @@ func (n *Signature) Pos() token.Loc {
 	}
 	return n.Loc
 }
-func (n *Signature) LocEnd() token.Loc { return n.Result.LocEnd() }
+func (n *Signature) LocEnd() token.Loc {
+	if n.Result == nil {
+		return n.Params.LocEnd()
+	}
+	return n.Result.LocEnd()
+}
 
 func (n *MemberAccess) Pos() token.Loc    { return n.X.Pos() }
 func (n *MemberAccess) LocEnd() token.Loc { return n.Selector.LocEnd() }
@@ -234,3 +254,9 @@ func (n *If) LocEnd() token.Loc {
 
 func (n *Else) Pos() token.Loc    { return n.Loc }
 func (n *Else) LocEnd() token.Loc { return n.Body.LocEnd() }
+
+func (n *Match) Pos() token.Loc    { return n.Loc }
+func (n *Match) LocEnd() token.Loc { return n.Body.LocEnd() }
+
+func (n *MatchArm) Pos() token.Loc    { return n.Patterns.Pos() }
+func (n *MatchArm) LocEnd() token.Loc { return n.X.LocEnd() }
@@ -1,6 +1,9 @@
 package ast
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+)
 
 //go:generate stringer -type=LiteralKind -linecomment -output=literal_kind_string.go
 type LiteralKind byte
@@ -16,3 +19,19 @@ const (
 func (kind LiteralKind) MarshalJSON() ([]byte, error) {
 	return json.Marshal(kind.String())
 }
+
+func (kind *LiteralKind) UnmarshalJSON(data []byte) error {
+	name := ""
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+
+	for k := LiteralKind(0); int(k) < len(_LiteralKind_index)-1; k++ {
+		if k.String() == name {
+			*kind = k
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid literal kind: '%s'", name)
+}
@@ -45,8 +45,10 @@ func (*ParenList) implNode()   {}
 func (*CurlyList) implNode()   {}
 func (*BracketList) implNode() {}
 
-func (*If) implNode()   {}
-func (*Else) implNode() {}
+func (*If) implNode()       {}
+func (*Else) implNode()     {}
+func (*Match) implNode()    {}
+func (*MatchArm) implNode() {}
 
 // Decls.
 
@@ -68,6 +70,7 @@ func (*ExprList) implNode()      {}
 func (*AttributeList) implNode() {}
 
 func (*While) implNode()    {}
+func (*For) implNode()      {}
 func (*Return) implNode()   {}
 func (*Break) implNode()    {}
 func (*Continue) implNode() {}
@@ -108,9 +111,20 @@ type (
 	//------------------------------------------------
 
 	While struct {
-		Cond Node
-		Body *CurlyList
-		Loc  token.Loc // `while` token.
+		Label *Ident // Optional.
+		Cond  Node
+		Body  *CurlyList
+		Loc   token.Loc // `while` token.
+	}
+
+	// Represents `for value in x {}` or `for index, value in x {}`.
+	For struct {
+		Label *Ident // Optional.
+		Index *Ident // Optional.
+		Value *Ident
+		X     Node // Range or array.
+		Body  *CurlyList
+		Loc   token.Loc // `for` token.
 	}
 
 	Return struct {
@@ -149,11 +163,33 @@ func (n *ExprList) LocEnd() token.Loc { return n.Exprs[len(n.Exprs)-1].LocEnd()
 func (n *AttributeList) Pos() token.Loc    { return n.Loc }
 func (n *AttributeList) LocEnd() token.Loc { return n.List.LocEnd() }
 
-func (n *While) Pos() token.Loc    { return n.Loc }
+func (n *While) Pos() token.Loc {
+	if n.Label != nil {
+		return n.Label.Pos()
+	}
+	return n.Loc
+}
 func (n *While) LocEnd() token.Loc { return n.Body.LocEnd() }
 
-func (n *Return) Pos() token.Loc    { return n.Loc }
-func (n *Return) LocEnd() token.Loc { return n.X.LocEnd() }
+func (n *For) Pos() token.Loc {
+	if n.Label != nil {
+		return n.Label.Pos()
+	}
+	return n.Loc
+}
+func (n *For) LocEnd() token.Loc { return n.Body.LocEnd() }
+
+func (n *Return) Pos() token.Loc { return n.Loc }
+func (n *Return) LocEnd() token.Loc {
+	if n.X != nil {
+		return n.X.LocEnd()
+	}
+	const length = uint32(len("return") - 1)
+	end := n.Loc
+	end.Char += length
+	end.Offset += uint64(length)
+	return end
+}
 
 func (n *Break) Pos() token.Loc { return n.Loc }
 func (n *Break) LocEnd() token.Loc {
@@ -185,11 +221,15 @@ func (n *Import) LocEnd() token.Loc { return n.Module.LocEnd() }
 // Additional methods for nodes.
 
 func (n *CommentGroup) Merged() string {
-	buf := strings.Builder{}
+	if n == nil {
+		return ""
+	}
+
+	lines := make([]string, len(n.Comments))
 
-	for _, comment := range n.Comments {
-		buf.WriteString(comment.Data[1:])
+	for i, comment := range n.Comments {
+		lines[i] = strings.TrimPrefix(comment.Data, " ")
 	}
 
-	return buf.String()
+	return strings.Join(lines, "\n")
 }
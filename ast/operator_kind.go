@@ -1,6 +1,9 @@
 package ast
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+)
 
 //go:generate stringer -type=OperatorKind -linecomment -output=operator_kind_string.go
 type OperatorKind byte
@@ -42,6 +45,8 @@ const (
 	OperatorBitShr        // >>
 	OperatorAnd           // and
 	OperatorOr            // or
+	OperatorRangeIncl     // ..
+	OperatorRangeExcl     // ..<
 
 	// Postfix.
 
@@ -49,6 +54,27 @@ const (
 	// OperatorUnwrap // !
 )
 
+// Reports whether the operator creates a range, i.e. '..' or '..<'.
+func (kind OperatorKind) IsRange() bool {
+	return kind == OperatorRangeIncl || kind == OperatorRangeExcl
+}
+
 func (kind OperatorKind) MarshalJSON() ([]byte, error) {
 	return json.Marshal(kind.String())
 }
+
+func (kind *OperatorKind) UnmarshalJSON(data []byte) error {
+	name := ""
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+
+	for k := OperatorKind(0); int(k) < len(_OperatorKind_index)-1; k++ {
+		if k.String() == name {
+			*kind = k
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid operator kind: '%s'", name)
+}
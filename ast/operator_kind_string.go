@@ -38,11 +38,13 @@ func _() {
 	_ = x[OperatorBitShr-27]
 	_ = x[OperatorAnd-28]
 	_ = x[OperatorOr-29]
+	_ = x[OperatorRangeIncl-30]
+	_ = x[OperatorRangeExcl-31]
 }
 
-const _OperatorKind_name = "UnknownOperator!-&*...=+=-=*=/=%=+-*/%==!=<<=>>=&|^<<>>andor"
+const _OperatorKind_name = "UnknownOperator!-&*...=+=-=*=/=%=+-*/%==!=<<=>>=&|^<<>>andor....<"
 
-var _OperatorKind_index = [...]uint8{0, 15, 16, 17, 18, 19, 22, 23, 25, 27, 29, 31, 33, 34, 35, 36, 37, 38, 40, 42, 43, 45, 46, 48, 49, 50, 51, 53, 55, 58, 60}
+var _OperatorKind_index = [...]uint8{0, 15, 16, 17, 18, 19, 22, 23, 25, 27, 29, 31, 33, 34, 35, 36, 37, 38, 40, 42, 43, 45, 46, 48, 49, 50, 51, 53, 55, 58, 60, 62, 65}
 
 func (i OperatorKind) String() string {
 	if i >= OperatorKind(len(_OperatorKind_index)-1) {
@@ -191,20 +191,27 @@ func (n *ModuleDecl) String() string {
 }
 
 func (n *VarDecl) String() string {
+	keyword := "var"
+	if n.IsVal {
+		keyword = "val"
+	}
+
 	if n.Value != nil {
 		return fmt.Sprintf(
-			"%s%svar %s = %s",
+			"%s%s%s %s = %s",
 			optionalComment(n.CommentGroup),
 			optionalAttributeList(n.Attrs),
+			keyword,
 			n.Binding.String(),
 			n.Value.String(),
 		)
 	}
 
 	return fmt.Sprintf(
-		"%s%svar %s",
+		"%s%s%s %s",
 		optionalComment(n.CommentGroup),
 		optionalAttributeList(n.Attrs),
+		keyword,
 		n.Binding.String(),
 	)
 }
@@ -279,12 +286,45 @@ func (n *Else) String() string {
 	return fmt.Sprintf("else %s", n.Body.String())
 }
 
+func (n *Match) String() string {
+	return fmt.Sprintf("match %s %s", n.X.String(), n.Body.String())
+}
+
+func (n *MatchArm) String() string {
+	return fmt.Sprintf("%s => %s", n.Patterns.String(), n.X.String())
+}
+
 func (n *While) String() string {
+	if n.Label != nil {
+		return fmt.Sprintf("%s: while %s %s", n.Label.String(), n.Cond.String(), n.Body.String())
+	}
+
 	return fmt.Sprintf("while %s %s", n.Cond.String(), n.Body.String())
 }
 
+func (n *For) String() string {
+	buf := strings.Builder{}
+
+	if n.Label != nil {
+		buf.WriteString(n.Label.String() + ": ")
+	}
+
+	buf.WriteString("for ")
+
+	if n.Index != nil {
+		buf.WriteString(n.Index.String() + ", ")
+	}
+
+	buf.WriteString(fmt.Sprintf("%s in %s %s", n.Value.String(), n.X.String(), n.Body.String()))
+	return buf.String()
+}
+
 func (n *Return) String() string {
-	return fmt.Sprintf("return %s", n.X.String())
+	if n.X != nil {
+		return fmt.Sprintf("return %s", n.X.String())
+	}
+
+	return "return"
 }
 
 func (n *Break) String() string {
@@ -159,6 +159,20 @@ func WalkTopDown(visit Visitor, tree Node) {
 
 		WalkTopDown(visit, n.Body)
 
+	case *Match:
+		assert.Ok(n.X != nil)
+		assert.Ok(n.Body != nil)
+
+		WalkTopDown(visit, n.X)
+		WalkTopDown(visit, n.Body)
+
+	case *MatchArm:
+		assert.Ok(n.Patterns != nil)
+		assert.Ok(n.X != nil)
+
+		walkExprList(visit, n.Patterns)
+		WalkTopDown(visit, n.X)
+
 	case *ModuleDecl:
 		assert.Ok(n.Name != nil)
 		assert.Ok(n.Body != nil)
@@ -280,9 +294,30 @@ func WalkTopDown(visit Visitor, tree Node) {
 		assert.Ok(n.Cond != nil)
 		assert.Ok(n.Body != nil)
 
+		if n.Label != nil {
+			WalkTopDown(visit, n.Label)
+		}
+
 		WalkTopDown(visit, n.Cond)
 		WalkTopDown(visit, n.Body)
 
+	case *For:
+		assert.Ok(n.Value != nil)
+		assert.Ok(n.X != nil)
+		assert.Ok(n.Body != nil)
+
+		if n.Label != nil {
+			WalkTopDown(visit, n.Label)
+		}
+
+		if n.Index != nil {
+			WalkTopDown(visit, n.Index)
+		}
+
+		WalkTopDown(visit, n.Value)
+		WalkTopDown(visit, n.X)
+		WalkTopDown(visit, n.Body)
+
 	case *Return:
 		if n.X != nil {
 			WalkTopDown(visit, n.X)
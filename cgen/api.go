@@ -3,16 +3,47 @@ package cgen
 import (
 	"bufio"
 	"io"
+	"time"
 
 	"github.com/saffage/jet/checker"
+	"github.com/saffage/jet/types"
 )
 
 func Generate(w io.Writer, m *checker.Module) []error {
-	gen := &generator{
-		Module: m,
-		out:    bufio.NewWriter(w),
+	return newGenerator(w, m).generate()
+}
+
+// Generates the module with the test harness as the 'main' function.
+// The harness runs each of the specified tests in a separate process
+// and reports the results. The 'main' function of the module is
+// generated as a regular function.
+func GenerateTests(w io.Writer, m *checker.Module, tests []*checker.Func) []error {
+	gen := newGenerator(w, m)
+	gen.tests = append([]*checker.Func{}, tests...)
+	return gen.generate()
+}
+
+// Generates the module with the benchmark harness as the 'main'
+// function. The harness runs each of the specified benchmarks for
+// at least the specified time and reports the time per iteration.
+func GenerateBenchmarks(w io.Writer, m *checker.Module, benchmarks []*checker.Func, benchTime time.Duration) []error {
+	gen := newGenerator(w, m)
+	gen.benchmarks = append([]*checker.Func{}, benchmarks...)
+	gen.benchTime = benchTime
+	return gen.generate()
+}
+
+func newGenerator(w io.Writer, m *checker.Module) *generator {
+	return &generator{
+		Module:          m,
+		out:             bufio.NewWriter(w),
+		names:           map[checker.Symbol]string{},
+		arrayTypes:      map[types.Type]string{},
+		declaredModules: map[*checker.Module]bool{},
 	}
+}
 
+func (gen *generator) generate() []error {
 	gen.out.WriteString(prelude)
 
 	if err := gen.out.Flush(); err != nil {
@@ -40,6 +71,14 @@ func Generate(w io.Writer, m *checker.Module) []error {
 		gen.funcDecl(mainFn)
 	}
 
+	switch {
+	case gen.tests != nil:
+		gen.testMain()
+
+	case gen.benchmarks != nil:
+		gen.benchMain()
+	}
+
 	gen.out.WriteString("\n/* TYPES */\n")
 	gen.out.WriteString(gen.typeSect.String())
 	// gen.out.WriteString("\n/* DATA */\n")
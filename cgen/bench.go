@@ -0,0 +1,73 @@
+package cgen
+
+import "fmt"
+
+// Generates the 'main' function that runs the benchmarks. The number
+// of iterations of each benchmark is increased until the run takes
+// at least the benchmark time.
+func (gen *generator) benchMain() {
+	gen.harnessMain(benchRunner, gen.benchmarks, fmt.Sprintf(
+		"__jet_run_benchmarks(funcs, %d, %du)",
+		len(gen.benchmarks),
+		gen.benchTime.Nanoseconds(),
+	))
+}
+
+const benchRunner = `
+#include <time.h>
+
+static uint64_t __jet_now_ns(void) {
+	struct timespec ts;
+	clock_gettime(CLOCK_MONOTONIC, &ts);
+	return (uint64_t)ts.tv_sec * 1000000000u + (uint64_t)ts.tv_nsec;
+}
+
+/* Returns the time of 'n' iterations of the benchmark. */
+static uint64_t __jet_run_benchmark(const __jet_func *bench, uint64_t n) {
+	uint64_t i, start = __jet_now_ns();
+
+	for (i = 0; i < n; i++) {
+		bench->fn();
+	}
+
+	return __jet_now_ns() - start;
+}
+
+static int __jet_run_benchmarks(const __jet_func *benchmarks, size_t count, uint64_t target_ns) {
+	size_t i;
+
+	for (i = 0; i < count; i++) {
+		uint64_t n = 1, elapsed;
+
+		for (;;) {
+			uint64_t next;
+
+			elapsed = __jet_run_benchmark(&benchmarks[i], n);
+
+			if (elapsed >= target_ns || n >= 1000000000u) {
+				break;
+			}
+
+			/* Predict the number of iterations with a 20% margin,
+			   but don't grow too fast. */
+			next = elapsed > 0 ? target_ns / elapsed * n * 6 / 5 : n * 100;
+
+			if (next > n * 100) {
+				next = n * 100;
+			}
+
+			if (next <= n) {
+				next = n + 1;
+			}
+
+			n = next;
+		}
+
+		printf("%-32s %12llu %14.1f ns/op\n", benchmarks[i].name,
+			(unsigned long long)n, (double)elapsed / (double)n);
+		fflush(stdout);
+	}
+
+	return 0;
+}
+`
@@ -2,6 +2,7 @@ package cgen
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/saffage/jet/ast"
 	"github.com/saffage/jet/internal/report"
@@ -77,9 +78,15 @@ func (gen *generator) builtInPrint(call *ast.BuiltInCall) string {
 	}
 }
 
+// The failed assertion is reported with the location in the Jet source.
 func (gen *generator) builtInAssert(call *ast.BuiltInCall) string {
-	expr := gen.ExprString(call.Args.(*ast.ParenList).Exprs[0])
-	return fmt.Sprintf("assert(%s)", expr)
+	arg := call.Args.(*ast.ParenList).Exprs[0]
+	return fmt.Sprintf(
+		"((%s) ? (void)0 : __jet_assert_fail(%s, %s))",
+		gen.ExprString(arg),
+		strconv.Quote(arg.String()),
+		strconv.Quote(gen.location(call.Pos())),
+	)
 }
 
 func (gen *generator) builtInAsPtr(call *ast.BuiltInCall) string {
@@ -54,6 +54,12 @@ func (gen *generator) ExprString(expr ast.Node) string {
 		}
 
 	case *ast.MemberAccess:
+		if ident, _ := node.X.(*ast.Ident); ident != nil {
+			if _, isModule := gen.SymbolOf(ident).(*checker.Module); isModule {
+				return gen.ExprString(node.Selector)
+			}
+		}
+
 		tv := gen.Types[node.X]
 		if tv == nil {
 			// Defined in another module?
@@ -346,6 +352,4 @@ func (gen *generator) constant(value constant.Value) string {
 	default:
 		panic("unreachable")
 	}
-
-	return "ERROR_CGEN__CONSTANT"
 }
@@ -10,69 +10,29 @@ import (
 )
 
 func (gen *generator) funcDecl(sym *checker.Func) {
-	t := sym.Type().(*types.Func)
-	declBuf := strings.Builder{}
 	tResultVar := types.Type(nil)
 
-	if sym.Name() == "main" {
+	if gen.isMain(sym) {
 		gen.codeSect.WriteString(fnMainHead)
 	} else {
-		if sym.IsExtern() {
-			declBuf.WriteString("extern ")
-		}
-
-		result := t.Result()
-
-		if result.Len() == 0 {
-			declBuf.WriteString("void")
-		} else if result.Len() == 1 {
-			declBuf.WriteString(gen.TypeString(result.Underlying()))
-			tResultVar = result.Underlying()
-		} else {
-			gen.errorf(sym, "tuple are not supported")
-			declBuf.WriteString("ERROR_CGEN__FUNC_TUPLE_RESULT")
-		}
+		head := gen.funcHead(sym)
 
-		declBuf.WriteByte(' ')
-		declBuf.WriteString(gen.name(sym))
-		declBuf.WriteByte('(')
-
-		// Gen params.
-		if len(sym.Params()) == 0 {
-			declBuf.WriteString("void")
-		} else {
-			for i, param := range sym.Params() {
-				if i != 0 {
-					declBuf.WriteString(", ")
-				}
-				if i == len(sym.Params())-1 && sym.Variadic() {
-					declBuf.WriteString("...")
-				} else {
-					// TODO this is not a valic place for the const qualifier,
-					// but currently its here for making `const char*` param.
-					if checker.GetAttribute(param, "ConstC") != nil {
-						declBuf.WriteString("const ")
-					}
-					declBuf.WriteString(gen.TypeString(param.Type()))
-					declBuf.WriteByte(' ')
-					declBuf.WriteString(gen.name(param))
-				}
-			}
-		}
-
-		declBuf.WriteByte(')')
-
-		gen.declFnsSect.WriteString(declBuf.String())
+		gen.declFnsSect.WriteString(head)
 		gen.declFnsSect.WriteString(";\n")
 
 		if sym.IsExtern() {
 			return
 		}
 
-		gen.codeSect.WriteString(declBuf.String())
+		if result := sym.Type().(*types.Func).Result(); result.Len() == 1 {
+			tResultVar = result.Underlying()
+		}
+
+		gen.codeSect.WriteString(head)
 	}
 
 	node := sym.Node().(*ast.FuncDecl)
+	gen.fn = sym
 
 	gen.codeSect.WriteString(" {\n")
 	gen.numIndent++
@@ -83,7 +43,7 @@ func (gen *generator) funcDecl(sym *checker.Func) {
 		gen.codeSect.WriteString(" __result;\n\n")
 	}
 
-	if sym.Name() == "main" {
+	if gen.isMain(sym) {
 		gen.indent(&gen.codeSect)
 		gen.codeSect.WriteString(fmt.Sprintf("init%s();\n", gen.Module.Name()))
 	}
@@ -92,7 +52,7 @@ func (gen *generator) funcDecl(sym *checker.Func) {
 		gen.indent(&gen.codeSect)
 
 		if tResultVar != nil && i == len(node.Body.Nodes)-1 {
-			gen.codeSect.WriteString(fmt.Sprintf("__result = %s;\n", gen.ExprString(stmt)))
+			gen.codeSect.WriteString(gen.resultStmt("__result", stmt))
 		} else {
 			gen.codeSect.WriteString(gen.StmtString(stmt))
 		}
@@ -110,3 +70,152 @@ func (gen *generator) funcDecl(sym *checker.Func) {
 	gen.numIndent--
 	gen.codeSect.WriteString("}\n")
 }
+
+// Generates the statement that assigns the value of the node to the
+// target, e.g. the trailing expression of the function body to
+// '__result'. The branches of 'if' and the arms of 'match' are assigned
+// separately, so they have no value in C and can end with 'return'.
+func (gen *generator) resultStmt(target string, node ast.Node) string {
+	switch node := node.(type) {
+	case *ast.Return:
+		return gen.returnStmt(node)
+
+	case *ast.Break, *ast.Continue:
+		return gen.StmtString(node)
+
+	case *ast.CurlyList:
+		return gen.resultBlock(target, node) + "\n"
+
+	case *ast.If:
+		buf := strings.Builder{}
+		buf.WriteString(fmt.Sprintf("if (%s) ", gen.ExprString(node.Cond)))
+		buf.WriteString(gen.resultBlock(target, node.Body))
+
+		if node.Else != nil {
+			buf.WriteString(" else ")
+			buf.WriteString(gen.resultStmt(target, node.Else.Body))
+		} else {
+			buf.WriteString("\n")
+		}
+
+		return buf.String()
+
+	case *ast.Match:
+		return gen.matchStmt(node, target)
+
+	default:
+		return fmt.Sprintf("%s = %s;\n", target, gen.ExprString(node))
+	}
+}
+
+func (gen *generator) resultBlock(target string, node *ast.CurlyList) string {
+	buf := strings.Builder{}
+	buf.WriteString("{\n")
+	gen.numIndent++
+
+	for i, stmt := range node.Nodes {
+		gen.indent(&buf)
+
+		if i == len(node.Nodes)-1 {
+			buf.WriteString(gen.resultStmt(target, stmt))
+		} else {
+			buf.WriteString(gen.StmtString(stmt))
+		}
+	}
+
+	gen.numIndent--
+	gen.indent(&buf)
+	buf.WriteString("}")
+	return buf.String()
+}
+
+// Generates 'return' from the current function. The value of the unit
+// type cannot be returned in C, so it is evaluated before 'return'.
+// The value of 'match' is assigned to '__result' before 'return'.
+func (gen *generator) returnStmt(node *ast.Return) string {
+	match, _ := node.X.(*ast.Match)
+
+	if gen.fn.Type().(*types.Func).Result().Len() == 1 {
+		if match != nil {
+			buf := strings.Builder{}
+			buf.WriteString(gen.matchStmt(match, "__result"))
+			gen.indent(&buf)
+			buf.WriteString("return __result;\n")
+			return buf.String()
+		}
+
+		return fmt.Sprintf("return %s;\n", gen.ExprString(node.X))
+	}
+
+	ret := "return;\n"
+	if gen.isMain(gen.fn) {
+		ret = "return 0;\n"
+	}
+
+	if node.X == nil {
+		return ret
+	}
+
+	buf := strings.Builder{}
+	buf.WriteString(gen.StmtString(node.X))
+	gen.indent(&buf)
+	buf.WriteString(ret)
+	return buf.String()
+}
+
+// Emits only a prototype of the function. Used for functions
+// defined in another module.
+func (gen *generator) funcProto(sym *checker.Func) {
+	gen.declFnsSect.WriteString(gen.funcHead(sym))
+	gen.declFnsSect.WriteString(";\n")
+}
+
+func (gen *generator) funcHead(sym *checker.Func) string {
+	t := sym.Type().(*types.Func)
+	declBuf := strings.Builder{}
+
+	if sym.IsExtern() {
+		declBuf.WriteString("extern ")
+	}
+
+	result := t.Result()
+
+	if result.Len() == 0 {
+		declBuf.WriteString("void")
+	} else if result.Len() == 1 {
+		declBuf.WriteString(gen.TypeString(result.Underlying()))
+	} else {
+		gen.errorf(sym, "tuple are not supported")
+		declBuf.WriteString("ERROR_CGEN__FUNC_TUPLE_RESULT")
+	}
+
+	declBuf.WriteByte(' ')
+	declBuf.WriteString(gen.name(sym))
+	declBuf.WriteByte('(')
+
+	// Gen params.
+	if len(sym.Params()) == 0 {
+		declBuf.WriteString("void")
+	} else {
+		for i, param := range sym.Params() {
+			if i != 0 {
+				declBuf.WriteString(", ")
+			}
+			if i == len(sym.Params())-1 && sym.Variadic() {
+				declBuf.WriteString("...")
+			} else {
+				// TODO this is not a valic place for the const qualifier,
+				// but currently its here for making `const char*` param.
+				if checker.GetAttribute(param, "ConstC") != nil {
+					declBuf.WriteString("const ")
+				}
+				declBuf.WriteString(gen.TypeString(param.Type()))
+				declBuf.WriteByte(' ')
+				declBuf.WriteString(gen.name(param))
+			}
+		}
+	}
+
+	declBuf.WriteByte(')')
+	return declBuf.String()
+}
@@ -2,12 +2,16 @@ package cgen
 
 import (
 	"bufio"
+	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/elliotchance/orderedmap/v2"
 	"github.com/saffage/jet/ast"
 	"github.com/saffage/jet/checker"
+	"github.com/saffage/jet/token"
+	"github.com/saffage/jet/types"
 )
 
 type generator struct {
@@ -21,6 +25,25 @@ type generator struct {
 	out          *bufio.Writer
 	errors       []error
 	numIndent    int
+
+	// Function whose body is being generated.
+	fn *checker.Func
+
+	// Loops enclosing the generated statement, the innermost is the
+	// last. The counter makes the names of their C labels unique.
+	loops     []*loopLabels
+	numLabels int
+
+	names           map[checker.Symbol]string
+	arrayTypes      map[types.Type]string
+	declaredModules map[*checker.Module]bool
+
+	// Tests or benchmarks called by the generated 'main' function.
+	// If set, the 'main' function of the module is generated as
+	// a regular one.
+	tests      []*checker.Func
+	benchmarks []*checker.Func
+	benchTime  time.Duration
 }
 
 func (gen *generator) defs(
@@ -38,7 +61,11 @@ func (gen *generator) defs(
 
 		switch sym := def.(type) {
 		case *checker.Var:
-			gen.varDecl(sym)
+			if declOnly {
+				gen.externVarDecl(sym)
+			} else {
+				gen.varDecl(sym)
+			}
 
 		case *checker.Const:
 			gen.constDecl(sym)
@@ -50,14 +77,28 @@ func (gen *generator) defs(
 			gen.enumDecl(sym)
 
 		case *checker.Func:
-			if sym.Name() == "main" && mainFunc == nil {
+			if declOnly {
+				// Definitions of the imported module are placed in
+				// its own C file.
+				if sym.Name() != "main" {
+					gen.funcProto(sym)
+				}
+			} else if gen.isMain(sym) && mainFunc == nil {
 				mainFunc = sym
 			} else {
 				gen.funcDecl(sym)
 			}
 
 		case *checker.Module:
-			gen.defs(sym.Defs, sym.Scope, true)
+			if !declOnly {
+				gen.declFnsSect.WriteString(fmt.Sprintf("void init%s(void);\n", sym.Name()))
+			}
+
+			// The module can be imported by several modules.
+			if !gen.declaredModules[sym] {
+				gen.declaredModules[sym] = true
+				gen.defs(sym.Defs, sym.Scope, true)
+			}
 
 		default:
 			panic("not implemented")
@@ -67,6 +108,17 @@ func (gen *generator) defs(
 	return mainFunc
 }
 
+// Reports whether the function is the entry point of the program.
+func (gen *generator) isMain(sym *checker.Func) bool {
+	return sym.Name() == "main" && gen.tests == nil && gen.benchmarks == nil
+}
+
+// Returns the location in the module file displayed by the generated
+// code, e.g. 'Main.jet:12:5'. The file is specified by its base name.
+func (gen *generator) location(loc token.Loc) string {
+	return fmt.Sprintf("%s.jet:%d:%d", gen.Module.Name(), loc.Line, loc.Char)
+}
+
 func (gen *generator) indent(w io.StringWriter) {
 	if gen.numIndent > 0 {
 		_, err := w.WriteString(strings.Repeat("\t", gen.numIndent))
@@ -97,6 +149,12 @@ typedef uint64_t Tu64;
 typedef float    Tf32;
 typedef double   Tf64;
 typedef uint8_t  Tbool;
+
+static inline void __jet_assert_fail(const char *expr, const char *loc) {
+	fflush(stdout);
+	fprintf(stderr, "%s: assertion failed: %s\n", loc, expr);
+	abort();
+}
 `
 
 const fnMainHead = "\nint main(const int argc, const char *const *const argv)"
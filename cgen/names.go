@@ -7,10 +7,8 @@ import (
 	"github.com/saffage/jet/checker"
 )
 
-var names = map[checker.Symbol]string{}
-
 func (gen *generator) name(sym checker.Symbol) string {
-	if name, ok := names[sym]; ok {
+	if name, ok := gen.names[sym]; ok {
 		return name
 	}
 	buf := strings.Builder{}
@@ -32,12 +30,13 @@ func (gen *generator) name(sym checker.Symbol) string {
 
 	gen.nameInternal(&buf, sym.Owner())
 	buf.WriteString(sym.Name())
-	names[sym] = buf.String()
+	gen.names[sym] = buf.String()
 	return buf.String()
 }
 
 func (gen *generator) nameInternal(w io.StringWriter, scope *checker.Scope) {
-	for scope != nil && scope != checker.Global {
+	// The root scope is the global scope.
+	for scope != nil && scope.Parent() != nil {
 		scopeName := scope.Name()
 		spaceIndex := strings.Index(scopeName, " ")
 
@@ -17,7 +17,10 @@ func (gen *generator) StmtString(stmt ast.Node) string {
 			buf.WriteString(gen.TypeString(sym.Type()))
 			buf.WriteString(" " + gen.name(sym) + ";\n")
 
-			if stmt.Value != nil {
+			if match, _ := stmt.Value.(*ast.Match); match != nil {
+				gen.indent(&buf)
+				buf.WriteString(gen.matchStmt(match, gen.name(sym)))
+			} else if stmt.Value != nil {
 				gen.indent(&buf)
 				buf.WriteString(gen.binary(
 					stmt.Binding.Name,
@@ -33,14 +36,13 @@ func (gen *generator) StmtString(stmt ast.Node) string {
 
 	case *ast.While:
 		buf.WriteString(fmt.Sprintf("while (%s) {\n", gen.ExprString(stmt.Cond)))
-		gen.numIndent++
-		for _, stmt := range stmt.Body.Nodes {
-			gen.indent(&buf)
-			buf.WriteString(gen.StmtString(stmt))
-		}
-		gen.numIndent--
-		gen.indent(&buf)
-		buf.WriteString("}\n")
+		gen.loopBody(&buf, stmt.Label, stmt.Body, "")
+
+	case *ast.For:
+		gen.forStmt(&buf, stmt)
+
+	case *ast.Match:
+		return gen.matchStmt(stmt, "")
 
 	case *ast.If:
 		buf.WriteString(fmt.Sprintf("if (%s) {\n", gen.ExprString(stmt.Cond)))
@@ -71,14 +73,266 @@ func (gen *generator) StmtString(stmt ast.Node) string {
 		buf.WriteString("}\n")
 
 	case *ast.Break:
+		if stmt.Label != nil {
+			loop := gen.loopOf(stmt.Label.Name)
+			loop.breakUsed = true
+			return fmt.Sprintf("goto %s;\n", loop.breakName())
+		}
+
+		if loop := gen.loops[len(gen.loops)-1]; loop.numSwitches > 0 {
+			// C 'break' would exit the 'switch' instead of the loop.
+			if loop.id == 0 {
+				gen.numLabels++
+				loop.id = gen.numLabels
+			}
+
+			loop.breakUsed = true
+			return fmt.Sprintf("goto %s;\n", loop.breakName())
+		}
+
 		return "break;\n"
 
 	case *ast.Continue:
+		if stmt.Label != nil {
+			loop := gen.loopOf(stmt.Label.Name)
+			loop.continueUsed = true
+			return fmt.Sprintf("goto %s;\n", loop.continueName())
+		}
+
 		return "continue;\n"
 
+	case *ast.Return:
+		return gen.returnStmt(stmt)
+
 	default:
 		return gen.ExprString(stmt) + ";\n"
 	}
 
 	return buf.String()
 }
+
+// C labels of the loop. The labels are generated only if they are used,
+// so the C compiler doesn't warn about the unused ones. The unlabelled
+// loop gets its id when 'break' inside the 'switch' needs its label.
+type loopLabels struct {
+	label        string
+	id           int
+	breakUsed    bool
+	continueUsed bool
+
+	// Number of 'switch' statements between the loop and the generated
+	// statement.
+	numSwitches int
+}
+
+func (loop *loopLabels) breakName() string {
+	return fmt.Sprintf("%s__break_%d", loop.label, loop.id)
+}
+
+func (loop *loopLabels) continueName() string {
+	return fmt.Sprintf("%s__continue_%d", loop.label, loop.id)
+}
+
+// Generates the body of the loop after its opening brace. The prologue,
+// if any, is placed before the statements of the body. The labelled loop
+// has the 'continue' target at the end of the body and the 'break'
+// target after the loop:
+//
+//	while (cond) {
+//	    ...
+//	    outer__continue_1:;
+//	}
+//	outer__break_1:;
+func (gen *generator) loopBody(buf *strings.Builder, label *ast.Ident, body *ast.CurlyList, prologue string) {
+	loop := &loopLabels{}
+
+	if label != nil {
+		gen.numLabels++
+		loop.label = label.Name
+		loop.id = gen.numLabels
+	}
+
+	gen.loops = append(gen.loops, loop)
+
+	gen.numIndent++
+	if prologue != "" {
+		gen.indent(buf)
+		buf.WriteString(prologue)
+	}
+	for _, stmt := range body.Nodes {
+		gen.indent(buf)
+		buf.WriteString(gen.StmtString(stmt))
+	}
+	if loop.continueUsed {
+		gen.indent(buf)
+		buf.WriteString(loop.continueName() + ":;\n")
+	}
+	gen.numIndent--
+	gen.indent(buf)
+	buf.WriteString("}\n")
+
+	gen.loops = gen.loops[:len(gen.loops)-1]
+
+	if loop.breakUsed {
+		gen.indent(buf)
+		buf.WriteString(loop.breakName() + ":;\n")
+	}
+}
+
+// Match is lowered to the C 'switch', each arm is a separate case that
+// ends with 'break':
+//
+//	switch (c) {
+//	case Color__Red:
+//	case Color__Green: {
+//	    ...
+//	    break;
+//	}
+//	default: {
+//	    ...
+//	    break;
+//	}
+//	}
+//
+// If the target is specified, the value of each arm is assigned to it,
+// so the match can be used as a value of a variable or of the function.
+func (gen *generator) matchStmt(node *ast.Match, target string) string {
+	buf := strings.Builder{}
+	buf.WriteString(fmt.Sprintf("switch (%s) {\n", gen.ExprString(node.X)))
+
+	if len(gen.loops) > 0 {
+		loop := gen.loops[len(gen.loops)-1]
+		loop.numSwitches++
+		defer func() { loop.numSwitches-- }()
+	}
+
+	for _, arm := range node.Body.Nodes {
+		arm := arm.(*ast.MatchArm)
+
+		for i, pattern := range arm.Patterns.Exprs {
+			if i != 0 {
+				buf.WriteString(":\n")
+			}
+
+			gen.indent(&buf)
+
+			if ident, _ := pattern.(*ast.Ident); ident != nil && ident.Name == "_" {
+				buf.WriteString("default")
+			} else {
+				buf.WriteString("case " + gen.ExprString(pattern))
+			}
+		}
+
+		buf.WriteString(": {\n")
+		gen.numIndent++
+
+		body := []ast.Node{arm.X}
+		if block, _ := arm.X.(*ast.CurlyList); block != nil {
+			body = block.Nodes
+		}
+
+		for i, stmt := range body {
+			gen.indent(&buf)
+
+			if target != "" && i == len(body)-1 {
+				buf.WriteString(gen.resultStmt(target, stmt))
+			} else {
+				buf.WriteString(gen.StmtString(stmt))
+			}
+		}
+
+		gen.indent(&buf)
+		buf.WriteString("break;\n")
+		gen.numIndent--
+		gen.indent(&buf)
+		buf.WriteString("}\n")
+	}
+
+	gen.indent(&buf)
+	buf.WriteString("}\n")
+	return buf.String()
+}
+
+// Loop over the range is lowered to the C 'for' loop, the end of the
+// range is evaluated once:
+//
+//	for (Ti32 i = 0, i__end = n; i < i__end; i++) {}
+//
+// Loop over the inclusive range exits after the iteration with the end
+// of the range, so the end can be the maximum value of the type:
+//
+//	for (Tu8 i = 0, i__end = 255, i__done = i > i__end; !i__done; i__done = i == i__end, i += !i__done) {}
+//
+// Loop over the array iterates over its indices, the element is copied
+// to the variable at the start of the body:
+//
+//	for (Ti32 __index = 0; __index < 3; __index++) {
+//	    Ti32 x = arr[__index];
+//	}
+func (gen *generator) forStmt(buf *strings.Builder, stmt *ast.For) {
+	if x, _ := stmt.X.(*ast.InfixOp); x != nil && x.Opr.Kind.IsRange() {
+		t := gen.TypeString(gen.Types[x.X].Type)
+		i := gen.loopVar(stmt.Value)
+
+		if x.Opr.Kind == ast.OperatorRangeIncl {
+			buf.WriteString(fmt.Sprintf(
+				"for (%s %s = %s, %s__end = %s, %s__done = %s > %s__end; !%s__done; %s__done = %s == %s__end, %s += !%s__done) {\n",
+				t, i, gen.ExprString(x.X), i, gen.ExprString(x.Y), i, i, i, i, i, i, i, i, i,
+			))
+		} else {
+			buf.WriteString(fmt.Sprintf(
+				"for (%s %s = %s, %s__end = %s; %s < %s__end; %s++) {\n",
+				t, i, gen.ExprString(x.X), i, gen.ExprString(x.Y), i, i, i,
+			))
+		}
+
+		gen.loopBody(buf, stmt.Label, stmt.Body, "")
+		return
+	}
+
+	array := types.AsArray(gen.Types[stmt.X].Type)
+	i := "__index"
+	prologue := ""
+
+	if stmt.Index != nil {
+		i = gen.loopVar(stmt.Index)
+	}
+
+	if sym, _ := gen.Defs.Get(stmt.Value); sym != nil {
+		prologue = fmt.Sprintf(
+			"%s %s = %s[%s];\n",
+			gen.TypeString(sym.Type()),
+			gen.name(sym),
+			gen.ExprString(stmt.X),
+			i,
+		)
+	}
+
+	buf.WriteString(fmt.Sprintf(
+		"for (%s %s = 0; %s < %d; %s++) {\n",
+		gen.TypeString(types.I32), i, i, array.Size(), i,
+	))
+	gen.loopBody(buf, stmt.Label, stmt.Body, prologue)
+}
+
+// Returns the C name of the loop variable. The variable named '_' is
+// not defined, so it gets a hidden name.
+func (gen *generator) loopVar(ident *ast.Ident) string {
+	if sym, _ := gen.Defs.Get(ident); sym != nil {
+		return gen.name(sym)
+	}
+
+	return "__index"
+}
+
+// Returns the innermost enclosing loop with the label. The label is
+// validated by the checker.
+func (gen *generator) loopOf(label string) *loopLabels {
+	for i := len(gen.loops) - 1; i >= 0; i-- {
+		if gen.loops[i].label == label {
+			return gen.loops[i]
+		}
+	}
+
+	panic("unreachable")
+}
@@ -0,0 +1,126 @@
+package cgen
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/saffage/jet/checker"
+)
+
+// Generates the 'main' function that runs the tests. On POSIX systems
+// each test is run in a forked process, so a failed assertion or a
+// crash doesn't stop the remaining tests.
+func (gen *generator) testMain() {
+	gen.harnessMain(testRunner, gen.tests, fmt.Sprintf("__jet_run_tests(funcs, %d)", len(gen.tests)))
+}
+
+// Generates the 'main' function that initializes the module and
+// passes the table of the specified functions to the runner.
+func (gen *generator) harnessMain(runner string, funcs []*checker.Func, run string) {
+	gen.codeSect.WriteString(harnessFuncType)
+	gen.codeSect.WriteString(runner)
+	gen.codeSect.WriteString(fnMainHead)
+	gen.codeSect.WriteString(" {\n")
+	gen.numIndent++
+
+	gen.indent(&gen.codeSect)
+	gen.codeSect.WriteString("static const __jet_func funcs[] = {\n")
+	gen.numIndent++
+
+	for _, fn := range funcs {
+		gen.indent(&gen.codeSect)
+		gen.codeSect.WriteString(fmt.Sprintf(
+			"{%s, %s, %s},\n",
+			strconv.Quote(fn.Name()),
+			strconv.Quote(gen.location(fn.Ident().Start)),
+			gen.name(fn),
+		))
+	}
+
+	if len(funcs) == 0 {
+		// Empty initializer list is not allowed in C.
+		gen.indent(&gen.codeSect)
+		gen.codeSect.WriteString("{0},\n")
+	}
+
+	gen.numIndent--
+	gen.indent(&gen.codeSect)
+	gen.codeSect.WriteString("};\n\n")
+	gen.indent(&gen.codeSect)
+	gen.codeSect.WriteString(fmt.Sprintf("init%s();\n", gen.Module.Name()))
+	gen.indent(&gen.codeSect)
+	gen.codeSect.WriteString(fmt.Sprintf("return %s;\n", run))
+
+	gen.numIndent--
+	gen.codeSect.WriteString("}\n")
+}
+
+const harnessFuncType = `
+typedef struct {
+	const char *name;
+	const char *loc;
+	void (*fn)(void);
+} __jet_func;
+`
+
+const testRunner = `
+#include <signal.h>
+
+#if !defined(_WIN32)
+#include <unistd.h>
+#include <sys/wait.h>
+#endif
+
+/* Returns 1 if the test passed. */
+static int __jet_run_test(const __jet_func *test) {
+#if defined(_WIN32)
+	test->fn();
+	return 1;
+#else
+	pid_t pid;
+	int status;
+
+	fflush(stdout);
+	fflush(stderr);
+	pid = fork();
+
+	if (pid < 0) {
+		perror("fork");
+		return 0;
+	}
+
+	if (pid == 0) {
+		test->fn();
+		fflush(stdout);
+		_exit(0);
+	}
+
+	if (waitpid(pid, &status, 0) < 0) {
+		perror("waitpid");
+		return 0;
+	}
+
+	if (WIFSIGNALED(status) && WTERMSIG(status) != SIGABRT) {
+		fprintf(stderr, "%s: terminated by signal %d\n", test->loc, WTERMSIG(status));
+	}
+
+	return WIFEXITED(status) && WEXITSTATUS(status) == 0;
+#endif
+}
+
+static int __jet_run_tests(const __jet_func *tests, size_t count) {
+	size_t i, failed = 0;
+
+	for (i = 0; i < count; i++) {
+		if (__jet_run_test(&tests[i])) {
+			printf("--- PASS: %s\n", tests[i].name);
+		} else {
+			printf("--- FAIL: %s (%s)\n", tests[i].name, tests[i].loc);
+			failed++;
+		}
+	}
+
+	printf("%s: %zu passed, %zu failed\n", failed ? "FAIL" : "ok", count - failed, failed);
+	return failed ? 1 : 0;
+}
+`
@@ -15,8 +15,6 @@ const (
 	_ErrorMetaType = "ERROR_CGEN__META_TYPE"
 )
 
-var arrayTypes = map[types.Type]string{}
-
 func (gen *generator) TypeString(t types.Type) string {
 	assert.Ok(!types.IsTypeDesc(t))
 
@@ -89,7 +87,7 @@ func (gen *generator) TypeString(t types.Type) string {
 		panic("not implemented")
 
 	case *types.Array:
-		if s, ok := arrayTypes[t]; ok {
+		if s, ok := gen.arrayTypes[t]; ok {
 			return s
 		}
 		elemTypeStr := gen.TypeString(t.ElemType())
@@ -97,7 +95,7 @@ func (gen *generator) TypeString(t types.Type) string {
 		gen.typeSect.WriteString(
 			fmt.Sprintf("typedef %s %s[%d];\n\n", elemTypeStr, typeStr, t.Size()),
 		)
-		arrayTypes[t] = typeStr
+		gen.arrayTypes[t] = typeStr
 		return typeStr
 
 	case *types.Ref:
@@ -14,16 +14,42 @@ func (gen *generator) varDecl(sym *checker.Var) {
 	gen.declVarsSect.WriteString(fmt.Sprintf("%s %s;\n", t, gen.name(sym)))
 }
 
+// Declares a global variable defined in another module.
+func (gen *generator) externVarDecl(sym *checker.Var) {
+	t := gen.TypeString(sym.Type())
+	gen.declVarsSect.WriteString(fmt.Sprintf("extern %s %s;\n", t, gen.name(sym)))
+}
+
 func (gen *generator) initFunc() string {
 	buf := strings.Builder{}
 	buf.WriteString(fmt.Sprintf("void init%s(void) {\n", gen.Module.Name()))
 	gen.numIndent++
 
+	// The module can be imported from several places, but must be
+	// initialized only once.
+	gen.indent(&buf)
+	buf.WriteString("static Tbool initialized = 0;\n")
+	gen.indent(&buf)
+	buf.WriteString("if (initialized) return;\n")
+	gen.indent(&buf)
+	buf.WriteString("initialized = 1;\n")
+
+	for _, imported := range gen.Imports {
+		gen.indent(&buf)
+		buf.WriteString(fmt.Sprintf("init%s();\n", imported.Name()))
+	}
+
 	for def := gen.Defs.Front(); def != nil; def = def.Next() {
 		def := def.Value
 
 		if _var, _ := def.(*checker.Var); _var != nil && _var.IsGlobal() && _var.Value() != nil {
 			gen.indent(&buf)
+
+			if match, _ := _var.Value().(*ast.Match); match != nil {
+				buf.WriteString(gen.matchStmt(match, gen.name(_var)))
+				continue
+			}
+
 			buf.WriteString(gen.binary(
 				_var.Node().(*ast.Binding).Name,
 				_var.Value(),
@@ -12,20 +12,23 @@ import (
 	"github.com/saffage/jet/internal/report"
 	"github.com/saffage/jet/parser"
 	"github.com/saffage/jet/scanner"
+	"github.com/saffage/jet/types"
 )
 
 var ErrorEmptyFileBuf = errors.New("empty file buffer or invalid file ID")
 
-func Check(cfg *config.Config, fileID config.FileID, node *ast.ModuleDecl) (*Module, []error) {
-	report.Hintf("checking module '%s'", cfg.Files[fileID].Name)
+func (env *Env) Check(fileID config.FileID, node *ast.ModuleDecl) (*Module, []error) {
+	report.Hintf("checking module '%s'", env.cfg.Files[fileID].Name)
 
-	module := NewModule(NewScope(Global, "module "+node.Name.Name), node)
+	module := NewModule(NewScope(env.Global, "module "+node.Name.Name), node)
+	module.fileID = fileID
 	check := &Checker{
 		module:         module,
 		scope:          module.Scope,
 		errors:         make([]error, 0),
 		isErrorHandled: true,
-		cfg:            cfg,
+		env:            env,
+		cfg:            env.cfg,
 		fileID:         fileID,
 	}
 
@@ -48,7 +51,7 @@ func Check(cfg *config.Config, fileID config.FileID, node *ast.ModuleDecl) (*Mod
 
 	module.completed = true
 
-	if config.FlagDumpCheckerState {
+	if env.cfg.DumpCheckerState {
 		err := os.Mkdir(".jet", os.ModePerm)
 		if err != nil && !os.IsExist(err) {
 			panic(err)
@@ -67,35 +70,104 @@ func Check(cfg *config.Config, fileID config.FileID, node *ast.ModuleDecl) (*Mod
 	return check.module, check.errors
 }
 
-func CheckFile(cfg *config.Config, fileID config.FileID) (*Module, []error) {
-	const ScannerFlags = scanner.SkipWhitespace | scanner.SkipComments
-	const ParserFlags = parser.DefaultFlags
+// Checks the expression in the scope of the module and returns its
+// type and value. The value is nil if the expression is not constant.
+//
+// The module must be checked by the same environment.
+func (env *Env) CheckExpr(m *Module, expr ast.Node) (*TypedValue, []error) {
+	check := &Checker{
+		module:         m,
+		scope:          m.Scope,
+		errors:         make([]error, 0),
+		isErrorHandled: true,
+		env:            env,
+		cfg:            env.cfg,
+		fileID:         m.fileID,
+	}
 
-	fi := cfg.Files[fileID]
-	if fi.Buf == nil {
-		return nil, []error{ErrorEmptyFileBuf}
+	t := check.typeOf(expr)
+	if len(check.errors) != 0 {
+		return nil, check.errors
 	}
 
-	tokens, errs := scanner.Scan(fi.Buf.Bytes(), fileID, ScannerFlags)
-	if len(errs) > 0 {
-		return nil, errs
+	if types.IsInvalid(t) {
+		err := NewError(expr, "expression has no type")
+		err.Code = report.CodeExpectedValue
+		return nil, []error{err}
 	}
 
-	nodeList, errs := parser.Parse(cfg, tokens, ParserFlags)
-	if len(errs) > 0 {
-		return nil, errs
+	if value := m.TypeInfo.ValueOf(expr); value != nil && value.Value != nil {
+		return value, nil
+	}
+
+	return &TypedValue{Type: t}, nil
+}
+
+// Checks the file and all of its imports. Each module is checked only
+// once, the result is cached by the path of the file.
+//
+// The import graph is loaded first, then the modules that don't depend
+// on each other are checked concurrently. The errors of all modules are
+// sorted by file and location.
+func (env *Env) CheckFile(fileID config.FileID) (*Module, []error) {
+	root := env.graph.load(env, fileID, nil)
+	nodes := root.postorder()
+
+	env.graph.check(env, nodes)
+
+	errs := []error{}
+
+	for _, node := range nodes {
+		errs = append(errs, node.errors...)
+		node.errors = nil
 	}
+
+	env.sortErrors(errs)
+
+	if len(errs) == 0 {
+		errs = nil
+	}
+
+	return root.module, errs
+}
+
+// Scans and parses the file. Returns the module declaration
+// even if the file contains syntax errors.
+func (env *Env) parse(fileID config.FileID) (*ast.ModuleDecl, []error) {
+	// Comments are kept, the parser attaches doc comments to the declarations.
+	const ScannerFlags = scanner.SkipWhitespace
+
+	parserFlags := parser.DefaultFlags
+	if env.cfg.TraceParser {
+		parserFlags |= parser.Trace
+	}
+
+	fi := env.cfg.Files[fileID]
+	if fi.Buf == nil {
+		return nil, []error{ErrorEmptyFileBuf}
+	}
+
+	// The parser skips the illegal tokens, so the scanner
+	// errors don't prevent parsing.
+	tokens, scanErrs := scanner.Scan(fi.Buf.Bytes(), fileID, ScannerFlags)
+
+	// On syntax errors, the parser returns the partial AST where the
+	// invalid nodes are replaced with [ast.BadNode], so the rest of
+	// the module can be checked.
+	nodeList, errs := parser.Parse(env.cfg, tokens, parserFlags)
+	errs = append(scanErrs, errs...)
+
 	if nodeList == nil {
-		// Empty file, nothing to check.
-		return NewModule(NewScope(nil, "module "+fi.Name), nil), nil
+		// Empty file.
+		return nil, errs
 	}
 
 	// printRecreatedAST(nodeList)
 
-	return Check(cfg, fileID, &ast.ModuleDecl{
+	return &ast.ModuleDecl{
 		Name: &ast.Ident{Name: fi.Name},
 		Body: nodeList,
-	})
+	}, errs
 }
 
 func printRecreatedAST(nodeList *ast.List) {
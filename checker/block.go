@@ -2,6 +2,7 @@ package checker
 
 import (
 	"github.com/saffage/jet/ast"
+	"github.com/saffage/jet/internal/report"
 	"github.com/saffage/jet/types"
 )
 
@@ -23,7 +24,7 @@ func (check *Checker) blockVisitor(expr *Block) ast.Visitor {
 				expr.t = types.Unit
 
 			case *ast.TypeAliasDecl, *ast.FuncDecl, *ast.ModuleDecl, *ast.ConstDecl:
-				check.errorf(decl, "a local scope can contain only variable declarations")
+				check.errorf(decl, report.CodeInvalidDecl, "a local scope can contain only variable declarations")
 				return nil
 
 			default:
@@ -33,12 +34,14 @@ func (check *Checker) blockVisitor(expr *Block) ast.Visitor {
 			return nil
 		}
 
-		t := check.typeOf(node)
-		if t == nil {
+		if _, isBad := node.(*ast.BadNode); isBad {
+			// The type of the block is unknown.
+			expr.t = types.Invalid
 			return nil
 		}
 
-		expr.t = t
+		check.allowMatch(node)
+		expr.t = check.typeOf(node)
 		return nil
 	}
 }
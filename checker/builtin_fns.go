@@ -3,6 +3,7 @@ package checker
 import (
 	"github.com/saffage/jet/ast"
 	"github.com/saffage/jet/constant"
+	"github.com/saffage/jet/internal/report"
 	"github.com/saffage/jet/types"
 )
 
@@ -57,7 +58,9 @@ func builtInMagic(node *ast.ParenList, args []*TypedValue) (*TypedValue, error)
 		return &TypedValue{types.NewTypeDesc(types.String), nil}, nil
 
 	default:
-		return nil, NewErrorf(node.Exprs[0], "unknown magic '%s'", *strval)
+		err := NewErrorf(node.Exprs[0], "unknown magic '%s'", *strval)
+		err.Code = report.CodeUnknownBuiltIn
+		return nil, err
 	}
 }
 
@@ -15,6 +15,18 @@ type Checker struct {
 	errors         []error
 	isErrorHandled bool
 
+	// Function whose body is being checked, used by 'return'.
+	fn *Func
+
+	// Labels of the loops enclosing the checked node, the innermost
+	// is the last. Loops without a label are represented by nil.
+	loops []*ast.Ident
+
+	// Matches that are used where they can be lowered to the C 'switch',
+	// see [Checker.allowMatch].
+	allowedMatches map[*ast.Match]bool
+
+	env    *Env
 	cfg    *config.Config
 	fileID config.FileID
 }
@@ -22,18 +34,20 @@ type Checker struct {
 // Type checks 'expr' and returns its type.
 // Also, the value of the expression will also be evaluated
 // (if possible) and stored in the 'check.Types' field.
-// If error was occured, result is undefined.
+// If error was occured, the result is [types.Invalid], so
+// the error is not reported again by the caller.
 func (check *Checker) typeOf(expr ast.Node) types.Type {
 	if v := check.valueOf(expr); v != nil {
 		return v.Type
 	}
 
-	if t := check.typeOfInternal(expr); t != nil {
-		check.setType(expr, t)
-		return t
+	t := check.typeOfInternal(expr)
+	if t == nil {
+		t = types.Invalid
 	}
 
-	return nil
+	check.setType(expr, t)
+	return t
 }
 
 func (check *Checker) valueOf(expr ast.Node) *TypedValue {
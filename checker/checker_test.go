@@ -0,0 +1,94 @@
+package checker
+
+import (
+	"bytes"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/saffage/jet/config"
+	"github.com/saffage/jet/internal/report"
+)
+
+// Checks the source as the module 'Main' and returns the errors.
+func checkSource(t *testing.T, source string) []error {
+	t.Helper()
+
+	cfg := config.New()
+	cfg.LibPath = filepath.Join("..", "lib")
+	cfg.Files[config.MainFileID] = config.FileInfo{
+		Name: "Main",
+		Path: "Main.jet",
+		Buf:  bytes.NewBufferString(source),
+	}
+
+	env, errs := NewEnv(cfg)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	_, errs = env.CheckFile(config.MainFileID)
+	return errs
+}
+
+// Checks the source and compares the codes of the errors.
+func expectCodes(t *testing.T, source string, want ...report.Code) []error {
+	t.Helper()
+
+	errs := checkSource(t, source)
+	codes := []report.Code{}
+
+	for _, err := range errs {
+		codes = append(codes, err.(*Error).Code)
+	}
+
+	if !slices.Equal(codes, want) {
+		t.Fatalf("unexpected codes of the errors; want %v, have %v: %v", want, codes, errs)
+	}
+
+	return errs
+}
+
+// Checks the source and compares the lines of the errors.
+func expectLines(t *testing.T, source string, want ...uint32) {
+	t.Helper()
+
+	errs := checkSource(t, source)
+	lines := []uint32{}
+
+	for _, err := range errs {
+		lines = append(lines, err.(*Error).Node.Pos().Line)
+	}
+
+	if !slices.Equal(lines, want) {
+		t.Fatalf("unexpected lines of the errors; want %v, have %v: %v", want, lines, errs)
+	}
+}
+
+// Symbols with an invalid type are reported once, the expressions
+// using them are not reported again.
+func TestInvalidType(t *testing.T) {
+	expectLines(t, `struct P {
+	x Foo
+	y i32
+}
+
+alias A = Bar
+
+func f(a Baz, b i32) i32 {
+	var v = a + 1
+	v.field + b + A.{}
+}
+
+func main() {
+	var p = P.{ x = 1; y = 2 }
+	f(p.x, unknown)
+	var z i32 = 1.5
+	z = z + 1
+}
+
+func g(x) {
+	@print(x)
+}
+`, 2, 6, 8, 15, 16, 20)
+}
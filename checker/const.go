@@ -30,35 +30,34 @@ func (v *Const) Node() ast.Node        { return nil }
 
 func (check *Checker) resolveConstDecl(node *ast.ConstDecl) {
 	value := check.valueOf(node.Binding.Value)
-	if value == nil {
-		check.errorf(node.Binding.Value, "value is not a constant expression")
-		return
-	}
+	if value == nil || value.Value == nil {
+		if !types.IsInvalid(check.typeOf(node.Binding.Value)) {
+			check.errorf(node.Binding.Value, report.CodeNotConstant, "value is not a constant expression")
+		}
 
-	tType := check.resolveVarType(node.Binding.Type, value.Type)
-	if tType == nil {
-		panic("unreachable")
+		// The constant is defined anyway, so its uses are not reported.
+		value = &TypedValue{types.Invalid, nil}
 	}
 
-	if value.Type != nil {
-		report.TaggedDebugf("checker", "const: value type: %s", value.Type)
-	}
+	tType := check.resolveVarType(node.Binding.Type, value.Type)
 
+	report.TaggedDebugf("checker", "const: value type: %s", value.Type)
 	report.TaggedDebugf("checker", "const: specified type: %s", tType)
 
-	if value.Type != nil && !value.Type.Equals(tType) {
+	if !value.Type.Equals(tType) {
 		check.errorf(
 			node.Binding.Name,
+			report.CodeTypeMismatch,
 			"type mismatch, expected '%s', got '%s'",
 			tType,
 			value.Type,
 		)
-		return
+		value = &TypedValue{types.Invalid, nil}
+	} else {
+		value.Type = tType
 	}
 
-	value.Type = tType
-
-	report.TaggedDebugf("checker", "const type: %s", tType)
+	report.TaggedDebugf("checker", "const type: %s", value.Type)
 	sym := NewConst(check.scope, value, node.Binding.Name)
 
 	if defined := check.scope.Define(sym); defined != nil {
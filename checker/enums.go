@@ -4,6 +4,7 @@ import (
 	"slices"
 
 	"github.com/saffage/jet/ast"
+	"github.com/saffage/jet/internal/report"
 	"github.com/saffage/jet/types"
 )
 
@@ -35,14 +36,17 @@ func (check *Checker) resolveEnumDecl(node *ast.EnumDecl) {
 	fields := make([]string, 0, len(node.Body.Nodes))
 
 	// TODO field names as distinct symbols.
-	for _, ident := range node.Body.Nodes {
-		ident, _ := ident.(*ast.Ident)
-		if ident == nil {
-			check.errorf(ident, "expected field identifier for enum")
-			continue
-		}
+	for _, member := range node.Body.Nodes {
+		switch member := member.(type) {
+		case *ast.Ident:
+			fields = append(fields, member.Name)
+
+		case *ast.BadNode:
+			// Reported by the parser.
 
-		fields = append(fields, ident.Name)
+		default:
+			check.errorf(member, report.CodeInvalidTypeDecl, "expected field identifier for enum")
+		}
 	}
 
 	t := types.NewTypeDesc(types.NewEnum(fields...))
@@ -58,13 +62,16 @@ func (check *Checker) resolveEnumDecl(node *ast.EnumDecl) {
 func (check *Checker) enumMember(node *ast.MemberAccess, t *types.Enum) types.Type {
 	fieldIdent, _ := node.Selector.(*ast.Ident)
 	if fieldIdent == nil {
-		check.errorf(node.Selector, "expected identifier for enum member")
+		check.errorf(node.Selector, report.CodeUnknownMember, "expected identifier for enum member")
 		return t
 	}
 
 	idx := slices.Index(t.Fields(), fieldIdent.Name)
 	if idx == -1 {
-		check.errorf(fieldIdent, "type has no member named '%s'", fieldIdent.Name)
+		err := NewErrorf(fieldIdent, "type has no member named '%s'", fieldIdent.Name)
+		err.Code = report.CodeUnknownMember
+		err.Fixes = suggestFix(fieldIdent, fieldIdent.Name, t.Fields())
+		check.addError(err)
 		return t
 	}
 
@@ -0,0 +1,69 @@
+package checker
+
+import (
+	"path/filepath"
+
+	"github.com/saffage/jet/config"
+)
+
+// Env is the environment of a single compilation. It contains
+// the global scope and the built-in modules.
+//
+// Environments don't share any state, so modules of the different
+// environments can be checked concurrently. The methods of a single
+// environment must not be called concurrently.
+type Env struct {
+	// Global scope containing the declarations of the built-in types.
+	Global *Scope
+
+	// This module contains the declaration of the Jet built-in types.
+	ModuleTypes *Module
+
+	// This module contains C type declarations and other tools for
+	// interacting with the C backend.
+	ModuleC *Module
+
+	cfg   *config.Config
+	graph moduleGraph
+}
+
+// Creates a new environment and checks the package 'builtin'
+// located in the directory specified by [config.Config.LibPath].
+func NewEnv(cfg *config.Config) (*Env, []error) {
+	env := &Env{
+		Global:      NewScope(nil, "global"),
+		ModuleTypes: NewModule(NewScope(nil, "module Types"), nil),
+		ModuleC:     NewModule(NewScope(nil, "module C"), nil),
+		cfg:         cfg,
+		graph:       newModuleGraph(),
+	}
+
+	if errs := env.checkBuiltInPkgs(); len(errs) != 0 {
+		return nil, errs
+	}
+
+	return env, nil
+}
+
+// Returns the configuration of the environment.
+func (env *Env) Config() *config.Config {
+	return env.cfg
+}
+
+// Returns the module located at the specified path if it was
+// already checked.
+func (env *Env) Module(path string) *Module {
+	if node := env.graph.nodes[absPath(path)]; node != nil && node.isChecked() {
+		return node.module
+	}
+
+	return nil
+}
+
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+
+	return filepath.Clean(path)
+}
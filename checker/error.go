@@ -1,17 +1,30 @@
 package checker
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
 
 	"github.com/saffage/jet/ast"
 	"github.com/saffage/jet/internal/report"
+	"github.com/saffage/jet/parser"
+	"github.com/saffage/jet/scanner"
 	"github.com/saffage/jet/token"
 )
 
 type Error struct {
+	Code    report.Code
 	Message string
 	Node    ast.Node
 	Notes   []*Error // TODO make a distinct type for the notes.
+	Fixes   []*Fix
+}
+
+// Fix is a suggested replacement of the node that resolves the error.
+type Fix struct {
+	Message string
+	Node    ast.Node
+	NewText string
 }
 
 func NewError(node ast.Node, message string) *Error {
@@ -31,23 +44,46 @@ func NewErrorf(node ast.Node, format string, args ...any) *Error {
 func (err *Error) Error() string { return err.Message }
 
 func (err *Error) Report() {
-	var start, end token.Loc
-	if err.Node != nil {
-		start, end = err.Node.Pos(), err.Node.LocEnd()
-	}
-	report.TaggedErrorAt("checker", start, end, err.Message)
+	report.Emit(err.Diagnostic())
+}
+
+func (err *Error) Diagnostic() *report.Diagnostic {
+	d := err.diagnostic(report.KindError)
 
 	for _, note := range err.Notes {
-		var start, end token.Loc
-		if note.Node != nil {
-			start, end = note.Node.Pos(), note.Node.LocEnd()
-		}
-		report.TaggedNoteAt("checker", start, end, note.Message)
+		d.Notes = append(d.Notes, note.diagnostic(report.KindNote))
 	}
+
+	for _, fix := range err.Fixes {
+		d.Fixes = append(d.Fixes, &report.Fix{
+			Message: fix.Message,
+			Start:   fix.Node.Pos(),
+			End:     fix.Node.LocEnd(),
+			NewText: fix.NewText,
+		})
+	}
+
+	return d
+}
+
+func (err *Error) diagnostic(kind report.Kind) *report.Diagnostic {
+	d := &report.Diagnostic{
+		Kind:    kind,
+		Tag:     "checker",
+		Code:    err.Code,
+		Message: err.Message,
+	}
+
+	if err.Node != nil {
+		d.Start, d.End = err.Node.Pos(), err.Node.LocEnd()
+	}
+
+	return d
 }
 
-func (check *Checker) errorf(node ast.Node, format string, args ...any) {
+func (check *Checker) errorf(node ast.Node, code report.Code, format string, args ...any) {
 	err := NewErrorf(node, format, args...)
+	err.Code = code
 	check.addError(err)
 }
 
@@ -55,3 +91,52 @@ func (check *Checker) addError(err error) {
 	check.errors = append(check.errors, err)
 	check.isErrorHandled = false
 }
+
+// Sorts the errors by file and location, so the order doesn't depend
+// on the order in which the modules were checked. Errors without
+// location are placed first.
+func (env *Env) sortErrors(errs []error) {
+	slices.SortStableFunc(errs, func(a, b error) int {
+		locA, okA := errorLoc(a)
+		locB, okB := errorLoc(b)
+
+		switch {
+		case !okA || !okB:
+			return cmp.Compare(boolToInt(okA), boolToInt(okB))
+
+		case locA.FileID != locB.FileID:
+			return cmp.Compare(env.cfg.Files[locA.FileID].Path, env.cfg.Files[locB.FileID].Path)
+
+		case locA.Line != locB.Line:
+			return cmp.Compare(locA.Line, locB.Line)
+
+		default:
+			return cmp.Compare(locA.Char, locB.Char)
+		}
+	})
+}
+
+func errorLoc(err error) (token.Loc, bool) {
+	switch err := err.(type) {
+	case *Error:
+		if err.Node != nil {
+			return err.Node.Pos(), true
+		}
+
+	case scanner.Error:
+		return err.Start, true
+
+	case parser.Error:
+		return err.Start, true
+	}
+
+	return token.Loc{}, false
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
@@ -39,44 +39,55 @@ func (check *Checker) resolveFuncDecl(node *ast.FuncDecl) {
 	isVariadic := false
 
 	for i, param := range sig.Params.Exprs {
+		var binding *ast.Binding
+
 		switch param := param.(type) {
 		case *ast.Binding:
-			var t types.Type
+			binding = param
 
-			if opr, _ := param.Type.(*ast.Operator); opr != nil && opr.Kind == ast.OperatorElipsis {
-				t = types.Any
-				isVariadic = true
-			} else if t = check.typeOf(param.Type); t == nil {
-				return
-			}
+		case *ast.BindingWithValue:
+			check.errorf(param, report.CodeInvalidFuncDecl, "parameters can't have a default value")
+			binding = param.Binding
 
-			if isVariadic && i != len(sig.Params.Exprs)-1 {
-				check.errorf(param.Name, "parameter with ... can only be the last in the list")
-			}
+		case *ast.BadNode:
+			// Reported by the parser.
+			tParams = append(tParams, types.Invalid)
+			continue
 
-			t = types.SkipTypeDesc(t)
-			tParams = append(tParams, t)
+		default:
+			panic(fmt.Sprintf("ill-formed AST: unexpected node type '%T'", param))
+		}
 
-			paramSym := NewVar(local, t, param, param.Name)
-			paramSym.isParam = true
+		var t types.Type
 
-			if defined := local.Define(paramSym); defined != nil {
-				check.errorf(param, "paramter with the same name was already defined")
-				return
-			}
+		if opr, _ := binding.Type.(*ast.Operator); opr != nil && opr.Kind == ast.OperatorElipsis {
+			t = types.Any
+			isVariadic = true
+		} else if binding.Type == nil {
+			check.errorf(binding, report.CodeInvalidFuncDecl, "parameter must have a type")
+			t = types.Invalid
+		} else {
+			t = check.resolveVarType(binding.Type, types.Invalid)
+		}
 
-			params = append(params, paramSym)
-			check.newDef(param.Name, paramSym)
-			report.TaggedDebugf("checker", "func: def param: %s", paramSym.Name())
-			report.TaggedDebugf("checker", "func: set param type: %s", t)
+		if isVariadic && i != len(sig.Params.Exprs)-1 {
+			check.errorf(binding.Name, report.CodeInvalidFuncDecl, "parameter with ... can only be the last in the list")
+		}
 
-		case *ast.BindingWithValue:
-			check.errorf(param, "parameters can't have a default value")
-			return
+		tParams = append(tParams, t)
 
-		default:
-			panic(fmt.Sprintf("ill-formed AST: unexpected node type '%T'", param))
+		paramSym := NewVar(local, t, binding, binding.Name)
+		paramSym.isParam = true
+
+		if defined := local.Define(paramSym); defined != nil {
+			check.errorf(binding, report.CodeAlreadyDefined, "paramter with the same name was already defined")
+			continue
 		}
+
+		params = append(params, paramSym)
+		check.newDef(binding.Name, paramSym)
+		report.TaggedDebugf("checker", "func: def param: %s", paramSym.Name())
+		report.TaggedDebugf("checker", "func: set param type: %s", t)
 	}
 
 	// Result.
@@ -84,12 +95,7 @@ func (check *Checker) resolveFuncDecl(node *ast.FuncDecl) {
 	tResult := types.Unit
 
 	if sig.Result != nil {
-		t := check.typeOf(sig.Result)
-		if t == nil {
-			return
-		}
-
-		tResult = types.NewTuple(types.SkipTypeDesc(t))
+		tResult = types.NewTuple(check.resolveVarType(sig.Result, types.Invalid))
 	}
 
 	// Produce function type.
@@ -100,9 +106,8 @@ func (check *Checker) resolveFuncDecl(node *ast.FuncDecl) {
 	report.TaggedDebugf("checker", "func: set type: %s", t)
 
 	if defined := check.scope.Define(sym); defined != nil {
-		err := errorAlreadyDefined(sym.Ident(), defined.Ident())
-		check.errors = append(check.errors, err)
-		return
+		check.addError(errorAlreadyDefined(sym.Ident(), defined.Ident()))
+		// Check the body anyway.
 	}
 
 	// Define function symbol inside their scope for recursion.
@@ -113,14 +118,27 @@ func (check *Checker) resolveFuncDecl(node *ast.FuncDecl) {
 
 	attrExternC := GetAttribute(sym, "ExternC")
 
+	attrTest, attrBench := GetAttribute(sym, "Test"), GetAttribute(sym, "Bench")
+
+	switch {
+	case attrTest != nil && attrBench != nil:
+		check.errorf(attrBench, report.CodeInvalidHarnessFunc, "function cannot be both a test and a benchmark")
+
+	case attrTest != nil && !check.harnessFunc(sym, "test", &check.module.Tests),
+		attrBench != nil && !check.harnessFunc(sym, "benchmark", &check.module.Benchmarks):
+		if sym.node.Body == nil {
+			// Already reported.
+			return
+		}
+	}
+
 	if isVariadic && attrExternC == nil {
-		check.errorf(sym.Ident(), "only a function with attribute @(ExternC) can be variadic")
-		return
+		check.errorf(sym.Ident(), report.CodeInvalidFuncDecl, "only a function with attribute @(ExternC) can be variadic")
 	}
 
 	if sym.node.Body == nil {
 		if attrExternC == nil {
-			check.errorf(sym.Ident(), "functions without body is not allowed")
+			check.errorf(sym.Ident(), report.CodeInvalidFuncDecl, "functions without body is not allowed")
 			return
 		}
 
@@ -129,22 +147,23 @@ func (check *Checker) resolveFuncDecl(node *ast.FuncDecl) {
 	}
 
 	if attrExternC != nil {
-		check.errorf(sym.Ident(), "functions with 'ExternC' attribute must have no body")
-		return
+		check.errorf(sym.Ident(), report.CodeInvalidFuncDecl, "functions with 'ExternC' attribute must have no body")
 	}
 
 	defer check.setScope(check.scope)
 	check.scope = local
 
+	defer func(fn *Func) { check.fn = fn }(check.fn)
+	check.fn = sym
+
 	tBody := check.typeOf(sym.node.Body)
-	if tBody == nil {
-		return
-	}
 
-	if !tBody.Equals(tResult) {
+	// The result of the body that ends with 'return' is already checked.
+	if !tBody.Equals(tResult) && !isTerminating(sym.node.Body) {
 		if len(sym.node.Body.Nodes) == 0 {
 			check.errorf(
 				sym.node.Body,
+				report.CodeTypeMismatch,
 				"expected expression of type '%s' for function result, got '%s' instead",
 				tResult,
 				tBody,
@@ -152,11 +171,35 @@ func (check *Checker) resolveFuncDecl(node *ast.FuncDecl) {
 		} else {
 			check.errorf(
 				sym.node.Body.Nodes[len(sym.node.Body.Nodes)-1],
+				report.CodeTypeMismatch,
 				"expected expression of type '%s' for function result, got '%s' instead",
 				tResult,
 				tBody,
 			)
 		}
-		return
 	}
 }
+
+// Checks the signature of the test or benchmark function and adds
+// it to the specified list. Such functions have no parameters and
+// no result. The kind is used in the error messages.
+func (check *Checker) harnessFunc(sym *Func, kind string, funcs *[]*Func) bool {
+	t := sym.Type().(*types.Func)
+
+	switch {
+	case sym.node.Body == nil:
+		check.errorf(sym.Ident(), report.CodeInvalidHarnessFunc, "%s function must have a body", kind)
+		return false
+
+	case t.Params().Len() != 0:
+		check.errorf(sym.node.Signature.Params, report.CodeInvalidHarnessFunc, "%s function must have no parameters", kind)
+		return false
+
+	case !t.Result().Equals(types.Unit):
+		check.errorf(sym.node.Signature.Result, report.CodeInvalidHarnessFunc, "%s function must have no result", kind)
+		return false
+	}
+
+	*funcs = append(*funcs, sym)
+	return true
+}
@@ -0,0 +1,214 @@
+package checker
+
+import (
+	"strings"
+
+	"github.com/saffage/jet/ast"
+	"github.com/saffage/jet/constant"
+	"github.com/saffage/jet/internal/report"
+	"github.com/saffage/jet/types"
+)
+
+// Type checks the 'match' expression. The arms are checked like the
+// branches of 'if', all of them must have the same type. The match must
+// be exhaustive, i.e. have an arm for each member of the enum or the
+// wildcard arm.
+func (check *Checker) typeOfMatch(node *ast.Match) types.Type {
+	if !check.allowedMatches[node] {
+		check.errorf(
+			node,
+			report.CodeInvalidMatch,
+			"'match' can be used only as a statement, a value of a variable or 'return'",
+		)
+		// Don't return, check the arms.
+	}
+
+	tOperand := check.typeOf(node.X)
+
+	if !types.IsInvalid(tOperand) && !types.IsEnum(tOperand) && !types.IsInteger(tOperand) {
+		check.errorf(node.X, report.CodeInvalidMatch, "expected enum or integer, got (%s) instead", tOperand)
+		tOperand = types.Invalid
+	}
+
+	tOperand = types.SkipUntyped(tOperand)
+
+	// Matched values by their keys, see [Checker.matchPattern].
+	matched := map[string]ast.Node{}
+	wildcard := ast.Node(nil)
+	tResult := types.Type(nil)
+
+	for _, node := range node.Body.Nodes {
+		arm, _ := node.(*ast.MatchArm)
+		if arm == nil {
+			panic("ill-formed AST")
+		}
+
+		for _, pattern := range arm.Patterns.Exprs {
+			if wildcard != nil {
+				err := NewError(pattern, "pattern is unreachable, the wildcard pattern matches all values")
+				err.Code = report.CodeInvalidMatch
+				err.Notes = []*Error{NewError(wildcard, "wildcard pattern is here")}
+				check.addError(err)
+				continue
+			}
+
+			if ident, _ := pattern.(*ast.Ident); ident != nil && ident.Name == "_" {
+				wildcard = pattern
+				continue
+			}
+
+			key := check.matchPattern(pattern, tOperand)
+			if key == "" {
+				continue
+			}
+
+			if previous := matched[key]; previous != nil {
+				err := NewErrorf(pattern, "value '%s' is already matched", key)
+				err.Code = report.CodeInvalidMatch
+				err.Notes = []*Error{NewError(previous, "previous pattern is here")}
+				check.addError(err)
+				continue
+			}
+
+			matched[key] = pattern
+		}
+
+		check.allowMatch(arm.X)
+		tArm := check.typeOf(arm.X)
+
+		switch {
+		case isTerminating(arm.X), isLoopJump(arm.X):
+			// The type of the arm that leaves the match doesn't matter.
+
+		case tResult == nil:
+			tResult = tArm
+
+		case !tArm.Equals(tResult) && !types.SkipUntyped(tArm).Equals(types.SkipUntyped(tResult)):
+			check.errorf(
+				arm.X,
+				report.CodeBranchMismatch,
+				"all arms must have the same type with first arm (%s), got (%s) instead",
+				tResult,
+				tArm,
+			)
+
+		case types.IsUntyped(tResult):
+			// The typed arm determines the type of the match.
+			tResult = tArm
+		}
+	}
+
+	if wildcard == nil && !types.IsInvalid(tOperand) {
+		check.checkExhaustive(node, tOperand, matched)
+	}
+
+	if tResult == nil {
+		// Each arm returns from the function.
+		return types.Unit
+	}
+
+	return tResult
+}
+
+// Allows the node to be 'match'. The match is lowered to the C 'switch'
+// statement, so it can be used only where each arm can assign or return
+// its value: as a statement, a value of a variable, a value of 'return'
+// and an arm of another match.
+func (check *Checker) allowMatch(node ast.Node) {
+	if match, _ := node.(*ast.Match); match != nil {
+		if check.allowedMatches == nil {
+			check.allowedMatches = map[*ast.Match]bool{}
+		}
+
+		check.allowedMatches[match] = true
+	}
+}
+
+// Checks the pattern of the 'match' arm and returns the key of the value
+// it matches, i.e. the name of the enum member or the integer. Returns
+// an empty string if the pattern is invalid.
+func (check *Checker) matchPattern(pattern ast.Node, tOperand types.Type) string {
+	t := check.typeOf(pattern)
+
+	if types.IsInvalid(t) || types.IsInvalid(tOperand) {
+		return ""
+	}
+
+	if !t.Equals(tOperand) && !types.SkipUntyped(t).Equals(tOperand) {
+		check.errorf(pattern, report.CodeTypeMismatch, "expected pattern of type (%s), got (%s) instead", tOperand, t)
+		return ""
+	}
+
+	if types.IsEnum(tOperand) {
+		if member, _ := pattern.(*ast.MemberAccess); member != nil {
+			ident, _ := member.Selector.(*ast.Ident)
+
+			if tv := check.module.Types[member.X]; ident != nil && tv != nil && types.IsTypeDesc(tv.Type) {
+				return ident.Name
+			}
+		}
+
+		check.errorf(pattern, report.CodeInvalidMatch, "expected enum member, e.g. 'Color.Red'")
+		return ""
+	}
+
+	value := check.module.Types[pattern]
+	if value == nil || value.Value == nil || value.Value.Kind() != constant.Int {
+		check.errorf(pattern, report.CodeNotConstant, "expected compile-time integer")
+		return ""
+	}
+
+	check.setType(pattern, tOperand)
+	return constant.AsInt(value.Value).String()
+}
+
+// Reports the 'match' without the wildcard arm that doesn't cover all
+// the values of the type.
+func (check *Checker) checkExhaustive(node *ast.Match, t types.Type, matched map[string]ast.Node) {
+	enum := types.AsEnum(t)
+	if enum == nil {
+		check.errorf(node, report.CodeNonExhaustiveMatch, "match on integers must have the wildcard arm '_'")
+		return
+	}
+
+	// The enum is named as in the patterns, e.g. 'Color'.
+	name := t.String()
+
+	for _, field := range enum.Fields() {
+		if member, _ := matched[field].(*ast.MemberAccess); member != nil {
+			name = member.X.String()
+			break
+		}
+	}
+
+	missing := []string{}
+
+	for _, field := range enum.Fields() {
+		if matched[field] == nil {
+			missing = append(missing, name+"."+field)
+		}
+	}
+
+	if len(missing) > 0 {
+		check.errorf(
+			node,
+			report.CodeNonExhaustiveMatch,
+			"match is not exhaustive, missing %s",
+			strings.Join(missing, ", "),
+		)
+	}
+}
+
+// Reports whether the arm ends with 'break' or 'continue'.
+func isLoopJump(node ast.Node) bool {
+	switch node := node.(type) {
+	case *ast.Break, *ast.Continue:
+		return true
+
+	case *ast.CurlyList:
+		return len(node.Nodes) > 0 && isLoopJump(node.Nodes[len(node.Nodes)-1])
+
+	default:
+		return false
+	}
+}
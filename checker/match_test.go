@@ -0,0 +1,55 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/saffage/jet/internal/report"
+)
+
+func TestMatch(t *testing.T) {
+	errs := expectCodes(t, `enum Color {
+	Red
+	Green
+	Blue
+}
+
+func main() {
+	var c = Color.Red
+	var n = 3
+
+	match c {
+		Color.Red => {}
+		Color.Green => {}
+	}
+	match c {
+		Color.Red, Color.Red => {}
+		_ => {}
+		Color.Blue => {}
+	}
+	match n {
+		n => {}
+		2 => {}
+	}
+	match 1.5 { _ => {} }
+	var x = match c {
+		Color.Red => 1
+		_ => true
+	}
+	@print(match n { _ => 1 })
+}
+`,
+		report.CodeNonExhaustiveMatch,
+		report.CodeInvalidMatch,
+		report.CodeInvalidMatch,
+		report.CodeNonExhaustiveMatch,
+		report.CodeNotConstant,
+		report.CodeInvalidMatch,
+		report.CodeBranchMismatch,
+		report.CodeInvalidMatch,
+	)
+
+	if msg := errs[0].Error(); !strings.Contains(msg, "missing Color.Blue") {
+		t.Errorf("expected the missing member in the message, have %q", msg)
+	}
+}
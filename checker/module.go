@@ -2,6 +2,8 @@ package checker
 
 import (
 	"github.com/saffage/jet/ast"
+	"github.com/saffage/jet/config"
+	"github.com/saffage/jet/internal/report"
 	"github.com/saffage/jet/types"
 )
 
@@ -19,7 +21,14 @@ type Module struct {
 	Scope   *Scope
 	Imports []*Module
 
+	// Functions with attribute @(Test) in the declaration order.
+	Tests []*Func
+
+	// Functions with attribute @(Bench) in the declaration order.
+	Benchmarks []*Func
+
 	node      *ast.ModuleDecl
+	fileID    config.FileID
 	kind      ModuleKind
 	completed bool
 }
@@ -40,6 +49,9 @@ func (m *Module) Name() string      { return m.node.Name.Name }
 func (m *Module) Ident() *ast.Ident { return m.node.Name }
 func (m *Module) Node() ast.Node    { return m.node }
 
+// Returns the ID of the module file.
+func (m *Module) FileID() config.FileID { return m.fileID }
+
 func (m *Module) TypeOf(expr ast.Node) types.Type {
 	if expr != nil {
 		if t := m.TypeInfo.TypeOf(expr); t != nil {
@@ -112,9 +124,12 @@ func (check *Checker) visit(node ast.Node) ast.Visitor {
 	case *ast.Import:
 		check.resolveImport(node)
 
+	case *ast.BadNode:
+		// Reported by the parser.
+
 	default:
 		// NOTE parser should prevent this in future.
-		check.errorf(node, "expected declaration")
+		check.errorf(node, report.CodeInvalidDecl, "expected declaration")
 		return nil
 	}
 
@@ -0,0 +1,236 @@
+package checker
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/saffage/jet/ast"
+	"github.com/saffage/jet/config"
+	"github.com/saffage/jet/internal/report"
+)
+
+// Graph of the module imports. Modules are indexed by the
+// absolute paths of their files.
+//
+// The graph is loaded sequentially, before the modules are
+// checked. After that it is only read, so the checkers of the
+// different modules can access it concurrently.
+type moduleGraph struct {
+	nodes map[string]*moduleNode
+
+	// Modules that are being loaded, in import order.
+	// Used to detect import cycles.
+	chain []importLink
+
+	// Imports that must be skipped by the checker because
+	// their errors are already reported (e.g. import cycles).
+	skipped map[*ast.Import]bool
+}
+
+type moduleNode struct {
+	fileID  config.FileID
+	name    string
+	decl    *ast.ModuleDecl // Nil if the file is empty or has syntax errors.
+	imports []*moduleNode
+	module  *Module
+	errors  []error
+
+	// Closed when the module is checked.
+	done chan struct{}
+}
+
+type importLink struct {
+	node *moduleNode
+	decl *ast.Import // Import that caused the loading, nil for the root module.
+}
+
+func newModuleGraph() moduleGraph {
+	return moduleGraph{
+		nodes:   map[string]*moduleNode{},
+		skipped: map[*ast.Import]bool{},
+	}
+}
+
+// Parses the file and recursively loads all of its imports.
+func (g *moduleGraph) load(env *Env, fileID config.FileID, importNode *ast.Import) *moduleNode {
+	fi := env.cfg.Files[fileID]
+	path := absPath(fi.Path)
+
+	if node := g.nodes[path]; node != nil {
+		return node
+	}
+
+	node := &moduleNode{
+		fileID: fileID,
+		name:   fi.Name,
+		done:   make(chan struct{}),
+	}
+	g.nodes[path] = node
+	g.chain = append(g.chain, importLink{node, importNode})
+	defer func() { g.chain = g.chain[:len(g.chain)-1] }()
+
+	node.decl, node.errors = env.parse(fileID)
+	if node.decl == nil {
+		return node
+	}
+
+	for _, decl := range node.decl.Body.(*ast.List).Nodes {
+		importDecl, ok := decl.(*ast.Import)
+		if !ok {
+			continue
+		}
+
+		importPath, err := env.resolveImportPath(fileID, importDecl.Module)
+		if err != nil || importPath == "" {
+			// Reported by the checker.
+			continue
+		}
+
+		if i := g.chainIndex(absPath(importPath)); i >= 0 {
+			node.errors = append(node.errors, g.errorImportCycle(importDecl, i))
+			g.skipped[importDecl] = true
+			continue
+		}
+
+		if imported := g.nodes[absPath(importPath)]; imported != nil {
+			node.imports = append(node.imports, imported)
+			continue
+		}
+
+		fileContent, err := os.ReadFile(importPath)
+		if err != nil {
+			readErr := NewErrorf(importDecl.Module, "while reading file: %s", err.Error())
+			readErr.Code = report.CodeModuleNotFound
+			node.errors = append(node.errors, readErr)
+			g.skipped[importDecl] = true
+			continue
+		}
+
+		importFileID := env.cfg.NextFileID()
+		env.cfg.Files[importFileID] = config.FileInfo{
+			Name: importDecl.Module.Name,
+			Path: importPath,
+			Buf:  bytes.NewBuffer(fileContent),
+		}
+
+		node.imports = append(node.imports, g.load(env, importFileID, importDecl))
+	}
+
+	return node
+}
+
+// Checks the modules concurrently. Each module is checked after
+// all of its imports.
+func (g *moduleGraph) check(env *Env, nodes []*moduleNode) {
+	wg := sync.WaitGroup{}
+
+	for _, node := range nodes {
+		if node.isChecked() {
+			continue
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			defer close(node.done)
+
+			for _, imported := range node.imports {
+				<-imported.done
+			}
+
+			if node.decl == nil {
+				if len(node.errors) == 0 {
+					// Empty file, nothing to check.
+					node.module = NewModule(NewScope(nil, "module "+node.name), nil)
+					node.module.fileID = node.fileID
+				}
+
+				return
+			}
+
+			m, errs := env.Check(node.fileID, node.decl)
+			node.module = m
+			node.errors = append(node.errors, errs...)
+		}()
+	}
+
+	wg.Wait()
+}
+
+// Returns the index of the module in the import chain or -1.
+func (g *moduleGraph) chainIndex(path string) int {
+	if node := g.nodes[path]; node != nil {
+		for i, link := range g.chain {
+			if link.node == node {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// Reports the import cycle starting from the module with the
+// specified index in the import chain. Each import of the
+// cycle is displayed as a note.
+func (g *moduleGraph) errorImportCycle(node *ast.Import, start int) *Error {
+	chain := g.chain[start:]
+	names := make([]string, 0, len(chain)+1)
+
+	for _, link := range chain {
+		names = append(names, link.node.name)
+	}
+
+	names = append(names, node.Module.Name)
+	err := NewErrorf(node.Module, "import cycle not allowed: %s", strings.Join(names, " -> "))
+	err.Code = report.CodeImportCycle
+
+	for i := 1; i < len(chain); i++ {
+		err.Notes = append(err.Notes, NewErrorf(
+			chain[i].decl.Module,
+			"module '%s' imports '%s'",
+			chain[i-1].node.name,
+			chain[i].node.name,
+		))
+	}
+
+	return err
+}
+
+func (node *moduleNode) isChecked() bool {
+	select {
+	case <-node.done:
+		return true
+
+	default:
+		return false
+	}
+}
+
+// Returns the module and all of its imports. Each module is
+// listed once, after all of its imports.
+func (node *moduleNode) postorder() []*moduleNode {
+	nodes := []*moduleNode{}
+	visited := map[*moduleNode]bool{}
+
+	var visit func(node *moduleNode)
+	visit = func(node *moduleNode) {
+		if visited[node] {
+			return
+		}
+
+		visited[node] = true
+
+		for _, imported := range node.imports {
+			visit(imported)
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	visit(node)
+	return nodes
+}
@@ -1,9 +1,7 @@
 package checker
 
 import (
-	"bytes"
 	"io/fs"
-	"os"
 	"path/filepath"
 
 	"github.com/saffage/jet/ast"
@@ -12,28 +10,26 @@ import (
 )
 
 func (check *Checker) resolveImport(node *ast.Import) {
-	path := check.resolveImportPath(node.Module)
-	if path == "" {
-		check.errorf(node.Module, "cannot find module named '%s'", node.Module)
+	if check.env.graph.skipped[node] {
 		return
 	}
 
-	fileContent, err := os.ReadFile(path)
+	path, err := check.env.resolveImportPath(check.fileID, node.Module)
 	if err != nil {
-		check.errorf(node.Module, "while reading file: %s", err.Error())
+		check.errorf(node.Module, report.CodeModuleNotFound, "while walking dir: %s", err.Error())
+		return
 	}
 
-	fileID := config.NextFileID()
-	check.cfg.Files[fileID] = config.FileInfo{
-		Name: node.Module.Name,
-		Path: path,
-		Buf:  bytes.NewBuffer(fileContent),
+	if path == "" {
+		check.errorf(node.Module, report.CodeModuleNotFound, "cannot find module named '%s'", node.Module)
+		return
 	}
 
-	m, errors := CheckFile(check.cfg, fileID)
-	if len(errors) != 0 {
-		report.Errors(errors...)
-		check.errorf(node.Module, "the module check was finished with errors")
+	// All imports are checked before the module (see [Env.CheckFile]).
+	m := check.env.Module(path)
+	if m == nil {
+		// The module has syntax errors.
+		return
 	}
 
 	if defined := check.module.Scope.Define(m); defined != nil {
@@ -44,15 +40,27 @@ func (check *Checker) resolveImport(node *ast.Import) {
 	check.newDef(node.Module, m)
 }
 
-func (check *Checker) resolveImportPath(ident *ast.Ident) string {
-	modulePath := ""
-	dir := filepath.Dir(check.cfg.Files[check.fileID].Path)
-	err := filepath.Walk(dir, makeWalkFunc(dir, ident.Name, &modulePath))
-	if err != nil {
-		check.errorf(ident, "while walking dir: %s", err.Error())
-		return ""
+// Searches for the module in the directory of the specified file
+// and then in the source directories of the project.
+func (env *Env) resolveImportPath(fileID config.FileID, ident *ast.Ident) (string, error) {
+	dirs := []string{filepath.Dir(env.cfg.Files[fileID].Path)}
+
+	if env.cfg.Project != nil {
+		dirs = append(dirs, env.cfg.Project.SourceDirs...)
+	}
+
+	for _, dir := range dirs {
+		modulePath := ""
+		err := filepath.Walk(dir, makeWalkFunc(dir, ident.Name, &modulePath))
+		if err != nil {
+			return "", err
+		}
+		if modulePath != "" {
+			return modulePath, nil
+		}
 	}
-	return modulePath
+
+	return "", nil
 }
 
 func makeWalkFunc(root string, expectedName string, result *string) filepath.WalkFunc {
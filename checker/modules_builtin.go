@@ -6,44 +6,33 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"sync"
 
 	"github.com/saffage/jet/config"
 	"github.com/saffage/jet/internal/report"
 )
 
-// This module contains the declaration of the Jet built-in types.
-var ModuleTypes *Module = NewModule(NewScope(nil, "module Types"), nil)
+var ErrorBuiltInPkg = errors.New("while checking package 'builtin'")
 
-// This module contains C type declarations and other tools for
-// interacting with the C backend.
-var ModuleC *Module = NewModule(NewScope(nil, "module C"), nil)
-
-var CheckBuiltInPkgs = sync.OnceFunc(func() {
+// Checks the modules of the package 'builtin' located in the core
+// library directory and defines their symbols in the global scope.
+func (env *Env) checkBuiltInPkgs() []error {
 	report.Hintf("checking package 'builtin'")
 
-	var libDir string
-
-	if config.FlagCoreLibPath != "" {
-		libDir = filepath.Clean(config.FlagCoreLibPath)
-	} else {
-		compilerDir := filepath.Dir(config.Exe)
-		libDir = filepath.Join(compilerDir, "lib")
-	}
+	libDir := env.cfg.LibPath
 
 	if dir, err := os.Stat(libDir); os.IsNotExist(err) || (dir != nil && !dir.IsDir()) {
-		panic(fmt.Errorf("invalid path to the core library: '%s'", libDir))
+		return []error{fmt.Errorf("invalid path to the core library: '%s'", libDir)}
 	}
 
 	builtinPkgDir := filepath.Join(libDir, "builtin")
 
 	if _, err := os.Stat(builtinPkgDir); os.IsNotExist(err) {
-		panic("package 'builtin' was not found")
+		return []error{errors.New("package 'builtin' was not found")}
 	}
 
 	builtInFiles, err := os.ReadDir(builtinPkgDir)
 	if err != nil {
-		panic(errors.Join(errors.New("while reading package 'builtin'"), err))
+		return []error{errors.Join(errors.New("while reading package 'builtin'"), err)}
 	}
 
 	var ModuleTypesFilepath, ModuleCFilepath string
@@ -57,36 +46,36 @@ var CheckBuiltInPkgs = sync.OnceFunc(func() {
 			ModuleCFilepath = filepath.Join(builtinPkgDir, entry.Name())
 
 		default:
-			panic(fmt.Sprintf("unexpected file in package 'builtin': '%s'", entry.Name()))
+			return []error{fmt.Errorf("unexpected file in package 'builtin': '%s'", entry.Name())}
 		}
 	}
 
 	switch {
 	case ModuleTypesFilepath == "":
-		panic("module 'Types' was not found")
+		return []error{errors.New("module 'Types' was not found")}
 
 	case ModuleCFilepath == "":
-		panic("module 'C' was not found")
+		return []error{errors.New("module 'C' was not found")}
 	}
 
 	moduleTypesContent, err := os.ReadFile(ModuleTypesFilepath)
 	if err != nil {
-		panic(err)
+		return []error{err}
 	}
 
 	moduleCContent, err := os.ReadFile(ModuleCFilepath)
 	if err != nil {
-		panic(err)
+		return []error{err}
 	}
 
-	moduleTypesFileID := config.NextFileID()
-	moduleCFileID := config.NextFileID()
-	config.Global.Files[moduleTypesFileID] = config.FileInfo{
+	moduleTypesFileID := env.cfg.NextFileID()
+	moduleCFileID := env.cfg.NextFileID()
+	env.cfg.Files[moduleTypesFileID] = config.FileInfo{
 		Name: "Types",
 		Path: ModuleTypesFilepath,
 		Buf:  bytes.NewBuffer(moduleTypesContent),
 	}
-	config.Global.Files[moduleCFileID] = config.FileInfo{
+	env.cfg.Files[moduleCFileID] = config.FileInfo{
 		Name: "C",
 		Path: ModuleCFilepath,
 		Buf:  bytes.NewBuffer(moduleCContent),
@@ -94,21 +83,16 @@ var CheckBuiltInPkgs = sync.OnceFunc(func() {
 
 	var errs []error
 
-	ModuleTypes, errs = CheckFile(config.Global, moduleTypesFileID)
-	checkErrors(errs)
+	env.ModuleTypes, errs = env.CheckFile(moduleTypesFileID)
+	if len(errs) != 0 {
+		return append([]error{ErrorBuiltInPkg}, errs...)
+	}
 
-	// ModuleC, errs = CheckFile(config.Global, moduleCFileID)
-	// checkErrors(errs)
+	// env.ModuleC, errs = env.CheckFile(moduleCFileID)
 
-	for _, sym := range ModuleTypes.Scope.symbols {
-		_ = Global.Define(sym)
+	for _, sym := range env.ModuleTypes.Scope.symbols {
+		_ = env.Global.Define(sym)
 	}
-})
 
-func checkErrors(errs []error) {
-	if len(errs) != 0 {
-		report.TaggedErrorf("internal", "while checking package 'builtin'")
-		report.Errors(errs...)
-		os.Exit(1)
-	}
+	return nil
 }
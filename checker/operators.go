@@ -27,6 +27,12 @@ func (check *Checker) prefix(node *ast.PrefixOp, tOperand types.Type) types.Type
 		}
 
 	case ast.OperatorAddrOf:
+		// The location can be mutated through the pointer.
+		if sym := check.immutableVarOf(node.X); sym != nil {
+			check.addError(errorImmutable(node.X, sym))
+			return nil
+		}
+
 		switch operand := node.X.(type) {
 		case *ast.Ident:
 			if sym, _ := check.symbolOf(operand).(*Var); sym != nil {
@@ -54,7 +60,7 @@ func (check *Checker) prefix(node *ast.PrefixOp, tOperand types.Type) types.Type
 			}
 		}
 
-		check.errorf(node.X, "expression is not an addressable location")
+		check.errorf(node.X, report.CodeNotAssignable, "expression is not an addressable location")
 		return nil
 
 	case ast.OperatorStar:
@@ -67,7 +73,7 @@ func (check *Checker) prefix(node *ast.PrefixOp, tOperand types.Type) types.Type
 			return ref.Base()
 		}
 
-		check.errorf(node.X, "expression is not a reference type")
+		check.errorf(node.X, report.CodeUndefinedOperator, "expression is not a reference type")
 		return nil
 
 	default:
@@ -76,6 +82,7 @@ func (check *Checker) prefix(node *ast.PrefixOp, tOperand types.Type) types.Type
 
 	check.errorf(
 		node.Opr,
+		report.CodeUndefinedOperator,
 		"operator '%s' is not defined for the type (%s)",
 		node.Opr.Kind,
 		tOperand,
@@ -86,15 +93,13 @@ func (check *Checker) prefix(node *ast.PrefixOp, tOperand types.Type) types.Type
 func (check *Checker) infix(node *ast.InfixOp, tOperandX, tOperandY types.Type) types.Type {
 	// TODO invalid type will be inferred is one of them is untyped
 	if !tOperandY.Equals(tOperandX) && !types.SkipUntyped(tOperandY).Equals(types.SkipUntyped(tOperandX)) {
-		check.errorf(node, "type mismatch (%s and %s)", tOperandX, tOperandY)
+		check.errorf(node, report.CodeTypeMismatch, "type mismatch (%s and %s)", tOperandX, tOperandY)
 		return nil
 	}
 
 	// Assignment operation doesn't have a value.
 	if node.Opr.Kind == ast.OperatorAssign {
-		if !check.assignable(node.X) {
-			check.errorf(node.X, "expression cannot be assigned")
-		}
+		check.mutable(node.X)
 		check.setType(node.Y, tOperandX)
 		return types.Unit
 	}
@@ -112,7 +117,7 @@ func (check *Checker) infix(node *ast.InfixOp, tOperandX, tOperandY types.Type)
 		}
 	}
 
-	check.errorf(node, "type mismatch (%s and %s)", tOperandX, tOperandY)
+	check.errorf(node, report.CodeTypeMismatch, "type mismatch (%s and %s)", tOperandX, tOperandY)
 	return nil
 }
 
@@ -159,9 +164,7 @@ func (check *Checker) infixPrimitive(
 			types.KindU64,
 			types.KindF32,
 			types.KindF64:
-			if !check.assignable(node.X) {
-				check.errorf(node.X, "expression cannot be assigned")
-			}
+			check.mutable(node.X)
 			return types.Unit
 		}
 
@@ -195,9 +198,7 @@ func (check *Checker) infixPrimitive(
 			types.KindU16,
 			types.KindU32,
 			types.KindU64:
-			if !check.assignable(node.X) {
-				check.errorf(node.X, "expression cannot be assigned")
-			}
+			check.mutable(node.X)
 			return types.Unit
 		}
 
@@ -247,7 +248,7 @@ func (check *Checker) assignable(node ast.Node) bool {
 		if operand != nil {
 			varSym, ok := check.symbolOf(operand).(*Var)
 			if !ok || varSym == nil {
-				check.errorf(operand, "identifier is not a variable")
+				check.errorf(operand, report.CodeNotAssignable, "identifier is not a variable")
 				return false
 			}
 
@@ -1,8 +1,11 @@
 package checker
 
-import "github.com/saffage/jet/ast"
+import (
+	"slices"
 
-var Global = NewScope(nil, "global")
+	"github.com/saffage/jet/ast"
+	"github.com/saffage/jet/internal/report"
+)
 
 type Scope struct {
 	parent  *Scope
@@ -83,8 +86,26 @@ func (scope *Scope) Member(name string) Symbol {
 	return nil
 }
 
+// Returns the sorted names of the symbols available in the scope,
+// including the symbols of the parent scopes.
+func (scope *Scope) Names() []string {
+	names := []string{}
+
+	for ; scope != nil; scope = scope.parent {
+		for name := range scope.symbols {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+
+	slices.Sort(names)
+	return names
+}
+
 func errorAlreadyDefined(ident, previous *ast.Ident) *Error {
 	err := NewErrorf(ident, "name '%s' is already defined in this scope", ident.Name)
+	err.Code = report.CodeAlreadyDefined
 
 	if previous != nil && previous.Start.Line > 0 {
 		err.Notes = []*Error{
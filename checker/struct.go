@@ -1,7 +1,6 @@
 package checker
 
 import (
-	"fmt"
 	"slices"
 	"strings"
 
@@ -34,46 +33,51 @@ func (sym *Struct) Ident() *ast.Ident { return sym.node.Name }
 func (sym *Struct) Node() ast.Node    { return sym.node }
 
 func (check *Checker) resolveStructDecl(node *ast.StructDecl) {
-	fields := make([]types.StructField, len(node.Body.Nodes))
+	fields := make([]types.StructField, 0, len(node.Body.Nodes))
 	local := NewScope(check.scope, "struct "+node.Name.Name)
 
 	if node.Body == nil {
 		panic("struct body cannot be nil")
 	}
 
-	for i, bodyNode := range node.Body.Nodes {
+	for _, bodyNode := range node.Body.Nodes {
 		binding, _ := bodyNode.(*ast.Binding)
 		if binding == nil {
-			check.errorf(binding, "expected field declaration")
-			return
-		}
+			if _, isBad := bodyNode.(*ast.BadNode); !isBad {
+				check.errorf(bodyNode, report.CodeInvalidTypeDecl, "expected field declaration")
+			}
 
-		tField := check.typeOf(binding.Type)
-		if tField == nil {
-			return
+			continue
 		}
 
-		if !types.IsTypeDesc(tField) {
-			check.errorf(binding.Type, "expected field type, got (%s) instead", tField)
-			return
-		}
+		// The field with invalid type is still defined,
+		// so its uses are not reported.
+		t := types.Type(types.Invalid)
+
+		if binding.Type == nil {
+			check.errorf(binding, report.CodeInvalidTypeDecl, "expected field type")
+		} else if tField := check.typeOf(binding.Type); types.IsTypeDesc(tField) {
+			if types.IsUntyped(tField) {
+				panic("typedesc cannot have an untyped base")
+			}
 
-		if types.IsUntyped(tField) {
-			panic("typedesc cannot have an untyped base")
+			t = types.AsTypeDesc(tField).Base()
+		} else if !types.IsInvalid(tField) {
+			check.errorf(binding.Type, report.CodeExpectedType, "expected field type, got (%s) instead", tField)
 		}
 
-		t := types.AsTypeDesc(tField).Base()
 		fieldSym := NewVar(local, t, binding, binding.Name)
 		fieldSym.isField = true
-		fields[i] = types.StructField{binding.Name.Name, t}
 
 		if defined := local.Define(fieldSym); defined != nil {
 			err := NewErrorf(fieldSym.Ident(), "duplicate field '%s'", fieldSym.Name())
+			err.Code = report.CodeAlreadyDefined
 			err.Notes = []*Error{NewError(defined.Ident(), "field was defined here")}
 			check.addError(err)
 			continue
 		}
 
+		fields = append(fields, types.StructField{Name: binding.Name.Name, Type: t})
 		check.newDef(binding.Name, fieldSym)
 	}
 
@@ -91,13 +95,13 @@ func (check *Checker) resolveStructDecl(node *ast.StructDecl) {
 func (check *Checker) structInit(node *ast.MemberAccess, typedesc *types.TypeDesc) types.Type {
 	tTypeStruct := types.AsStruct(typedesc.Base())
 	if tTypeStruct == nil {
-		check.errorf(node.X, "type (%s) is not a struct", typedesc)
+		check.errorf(node.X, report.CodeInvalidStructInit, "type (%s) is not a struct", typedesc)
 		return nil
 	}
 
 	initList, _ := node.Selector.(*ast.CurlyList)
 	if initList == nil {
-		check.errorf(node.Selector, "expected struct initializer")
+		check.errorf(node.Selector, report.CodeInvalidStructInit, "expected struct initializer")
 		return nil
 	}
 
@@ -109,29 +113,26 @@ func (check *Checker) structInit(node *ast.MemberAccess, typedesc *types.TypeDes
 	for _, init := range initList.Nodes {
 		switch init := init.(type) {
 		case *ast.Ident:
-			panic("field initializer shortcut is not implemented")
+			check.errorf(init, report.CodeNotImplemented, "field initializer shortcut is not implemented")
 
 		case *ast.InfixOp:
 			if init.Opr.Kind != ast.OperatorAssign {
-				panic(fmt.Sprintf(
-					"unexpected infix expression '%s' in struct initializer",
-					init.Opr,
-				))
+				check.errorf(init.Opr, report.CodeInvalidStructInit, "expected '=' in field initializer, found '%s'", init.Opr)
+				continue
 			}
 
+			tFieldValue := check.typeOf(init.Y)
+
 			fieldNameNode, _ := init.X.(*ast.Ident)
 			if fieldNameNode == nil {
-				panic("expected identifier for field name")
-			}
-
-			tFieldValue := check.typeOf(init.Y)
-			if tFieldValue == nil {
-				return nil
+				check.errorf(init.X, report.CodeInvalidStructInit, "expected identifier for field name")
+				continue
 			}
 
 			if _, hasField := initFields[fieldNameNode.Name]; hasField {
 				// TODO point to the previous field assignment.
 				err := NewErrorf(fieldNameNode, "field '%s' is already specified", fieldNameNode.Name)
+				err.Code = report.CodeInvalidStructInit
 				check.addError(err)
 			} else {
 				initFields[fieldNameNode.Name] = tFieldValue
@@ -155,8 +156,11 @@ func (check *Checker) structInit(node *ast.MemberAccess, typedesc *types.TypeDes
 			// 	panic("unreachable")
 			// }
 
+		case *ast.BadNode:
+			// Reported by the parser.
+
 		default:
-			panic(fmt.Sprintf("unexpected node of type '%T' in struct initializer", init))
+			check.errorf(init, report.CodeInvalidStructInit, "expected field initializer")
 		}
 	}
 
@@ -174,6 +178,7 @@ func (check *Checker) structInit(node *ast.MemberAccess, typedesc *types.TypeDes
 		if !tInit.Equals(field.Type) {
 			check.errorf(
 				initFieldValues[field.Name],
+				report.CodeTypeMismatch,
 				"type mismatch, expected (%s) for field '%s', got (%s) instead",
 				field.Type,
 				field.Name,
@@ -196,12 +201,14 @@ func (check *Checker) structInit(node *ast.MemberAccess, typedesc *types.TypeDes
 	if len(missingFieldNames) == 1 {
 		check.errorf(
 			node.Selector,
+			report.CodeInvalidStructInit,
 			"missing field '%s' in struct initializer",
 			missingFieldNames[0],
 		)
 	} else if len(missingFieldNames) > 1 {
 		check.errorf(
 			node.Selector,
+			report.CodeInvalidStructInit,
 			"missing fields '%s' in struct initializer",
 			strings.Join(missingFieldNames, "', '"),
 		)
@@ -209,11 +216,14 @@ func (check *Checker) structInit(node *ast.MemberAccess, typedesc *types.TypeDes
 
 	if len(initFields) > 0 {
 		for name := range initFields {
-			check.errorf(
+			err := NewErrorf(
 				initFieldNames[name],
 				"extra field '%s' in struct initializer",
 				name,
 			)
+			err.Code = report.CodeInvalidStructInit
+			err.Fixes = suggestFix(initFieldNames[name], name, missingFieldNames)
+			check.addError(err)
 		}
 	}
 
@@ -223,13 +233,13 @@ func (check *Checker) structInit(node *ast.MemberAccess, typedesc *types.TypeDes
 
 func (check *Checker) structMember(operand, selector ast.Node, t *types.Struct) types.Type {
 	if t == types.String {
-		check.errorf(operand, "member access on string type is not implemented")
+		check.errorf(operand, report.CodeNotImplemented, "member access on string type is not implemented")
 		return nil
 	}
 
 	fieldIdent, _ := selector.(*ast.Ident)
 	if fieldIdent == nil {
-		check.errorf(selector, "expected field identifier")
+		check.errorf(selector, report.CodeUnknownMember, "expected field identifier")
 		return nil
 	}
 
@@ -238,7 +248,10 @@ func (check *Checker) structMember(operand, selector ast.Node, t *types.Struct)
 	})
 
 	if fieldIndex == -1 {
-		check.errorf(selector, "unknown field '%s'", fieldIdent.Name)
+		err := NewErrorf(selector, "unknown field '%s'", fieldIdent.Name)
+		err.Code = report.CodeUnknownMember
+		err.Fixes = suggestFix(fieldIdent, fieldIdent.Name, fieldNames(t))
+		check.addError(err)
 		return nil
 	}
 
@@ -258,3 +271,13 @@ func (check *Checker) structMember(operand, selector ast.Node, t *types.Struct)
 
 	return t.Fields()[fieldIndex].Type
 }
+
+func fieldNames(t *types.Struct) []string {
+	names := make([]string, len(t.Fields()))
+
+	for i, field := range t.Fields() {
+		names[i] = field.Name
+	}
+
+	return names
+}
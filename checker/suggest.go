@@ -0,0 +1,78 @@
+package checker
+
+import (
+	"fmt"
+
+	"github.com/saffage/jet/ast"
+)
+
+// Returns the fixes for [Error.Fixes] that replace the node with the
+// candidate that is the most similar to the name, or nil if there is
+// no such candidate.
+func suggestFix(node ast.Node, name string, candidates []string) []*Fix {
+	if suggestion := suggest(name, candidates); suggestion != "" {
+		return []*Fix{{
+			Message: fmt.Sprintf("did you mean '%s'?", suggestion),
+			Node:    node,
+			NewText: suggestion,
+		}}
+	}
+
+	return nil
+}
+
+// Returns the candidate with the smallest edit distance to the name.
+// Candidates that differ too much are ignored, so short names have
+// no suggestions. The first of the equally similar candidates is
+// returned.
+func suggest(name string, candidates []string) string {
+	maxDistance := (len(name) + 1) / 3
+	suggestion := ""
+
+	for _, candidate := range candidates {
+		if candidate == name {
+			continue
+		}
+
+		if distance := editDistance(name, candidate); distance <= maxDistance {
+			maxDistance = distance - 1
+			suggestion = candidate
+		}
+	}
+
+	return suggestion
+}
+
+// Returns the number of insertions, deletions, substitutions and
+// transpositions of adjacent characters required to change 'a' to 'b'.
+func editDistance(a, b string) int {
+	// Only the last 3 rows of the matrix are stored.
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+
+		prev2, prev, curr = prev, curr, prev2
+	}
+
+	return prev[len(b)]
+}
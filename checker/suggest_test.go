@@ -0,0 +1,38 @@
+package checker
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSuggestions(t *testing.T) {
+	errs := checkSource(t, `struct Point {
+	x i32
+	y i32
+}
+
+enum Color {
+	Red
+	Green
+}
+
+func main() {
+	var count = 1
+	var p = Point.{ x = 1; yy = 2 }
+	var c = Color.Gren
+	cuont + p.xx + z
+}
+`)
+	fixes := []string{}
+
+	for _, err := range errs {
+		for _, fix := range err.(*Error).Fixes {
+			fixes = append(fixes, fix.NewText)
+		}
+	}
+
+	// The missing field and 'z' have no suggestions.
+	if want := []string{"y", "Green", "count", "x"}; !slices.Equal(fixes, want) {
+		t.Fatalf("unexpected suggestions; want %v, have %v: %v", want, fixes, errs)
+	}
+}
@@ -2,6 +2,7 @@ package checker
 
 import (
 	"github.com/saffage/jet/ast"
+	"github.com/saffage/jet/internal/report"
 	"github.com/saffage/jet/types"
 )
 
@@ -29,15 +30,15 @@ func (sym *TypeAlias) Node() ast.Node    { return sym.node }
 
 func (check *Checker) resolveTypeAliasDecl(node *ast.TypeAliasDecl) {
 	t := check.typeOf(node.Expr)
-	if t == nil {
-		return
-	}
-
 	typedesc := types.AsTypeDesc(t)
 
 	if typedesc == nil {
-		check.errorf(node.Expr, "expression is not a type")
-		return
+		if !types.IsInvalid(t) {
+			check.errorf(node.Expr, report.CodeExpectedType, "expression is not a type")
+		}
+
+		// The alias is defined anyway, so its uses are not reported.
+		typedesc = types.NewTypeDesc(types.Invalid)
 	}
 
 	sym := NewTypeAlias(check.scope, typedesc, node)
@@ -13,7 +13,7 @@ import (
 )
 
 // Type checks 'expr' and returns its type.
-// If error was occured, result is undefined
+// If error was occured, the result is nil.
 func (check *Checker) typeOfInternal(expr ast.Node) types.Type {
 	switch node := expr.(type) {
 	case nil:
@@ -22,10 +22,14 @@ func (check *Checker) typeOfInternal(expr ast.Node) types.Type {
 	case ast.Decl:
 		panic("declaration must be handled somewhere else")
 
-	case *ast.BadNode,
-		*ast.Comment,
+	case *ast.BadNode:
+		// Reported by the parser.
+		return nil
+
+	case *ast.Comment,
 		*ast.CommentGroup,
 		*ast.Else,
+		*ast.MatchArm,
 		*ast.List,
 		*ast.ExprList,
 		*ast.AttributeList:
@@ -86,20 +90,24 @@ func (check *Checker) typeOfInternal(expr ast.Node) types.Type {
 	case *ast.If:
 		return check.typeOfIf(node)
 
+	case *ast.Match:
+		return check.typeOfMatch(node)
+
 	case *ast.While:
 		return check.typeOfWhile(node)
 
-	// NOTE implementation of break & continue are not finished.
+	case *ast.For:
+		return check.typeOfFor(node)
+
+	case *ast.Return:
+		return check.typeOfReturn(node)
+
 	case *ast.Break:
-		if node.Label != nil {
-			check.errorf(node.Label, "labels are not implemented")
-		}
+		check.checkLoopJump(node, "break", node.Label)
 		return types.Unit
 
 	case *ast.Continue:
-		if node.Label != nil {
-			check.errorf(node.Label, "labels are not implemented")
-		}
+		check.checkLoopJump(node, "continue", node.Label)
 		return types.Unit
 
 	default:
@@ -121,10 +129,14 @@ func (check *Checker) typeOfIdent(node *ast.Ident) types.Type {
 			return sym.Type()
 		}
 
-		check.errorf(node, "expression has no type")
+		check.errorf(node, report.CodeExpectedValue, "expression has no type")
 		return nil
 	}
 
+	err := NewError(node, "identifier is undefined")
+	err.Code = report.CodeUndefinedName
+	err.Fixes = suggestFix(node, node.Name, check.scope.Names())
+	check.addError(err)
 	return nil
 }
 
@@ -159,22 +171,17 @@ func (check *Checker) typeOfBuiltInCall(node *ast.BuiltInCall) types.Type {
 	}
 
 	if builtIn == nil {
-		check.errorf(node.Name, "unknown built-in function '@%s'", node.Name.Name)
+		check.errorf(node.Name, report.CodeUnknownBuiltIn, "unknown built-in function '@%s'", node.Name.Name)
 		return nil
 	}
 
 	args, _ := node.Args.(*ast.ParenList)
 	if args == nil {
-		check.errorf(node.Args, "block as built-in function argument is not yet supported")
+		check.errorf(node.Args, report.CodeNotImplemented, "block as built-in function argument is not yet supported")
 		return nil
 	}
 
-	tArgList := check.typeOfParenList(args)
-	if tArgList == nil {
-		return nil
-	}
-
-	tArgs, _ := tArgList.(*types.Tuple)
+	tArgs, _ := check.typeOfParenList(args).(*types.Tuple)
 	if tArgs == nil {
 		return nil
 	}
@@ -186,7 +193,12 @@ func (check *Checker) typeOfBuiltInCall(node *ast.BuiltInCall) types.Type {
 			n = args.Exprs[idx]
 		}
 
-		check.errorf(n, err.Error())
+		check.errorf(n, report.CodeInvalidArguments, err.Error())
+		return nil
+	}
+
+	if slices.ContainsFunc(tArgs.Types(), types.IsInvalid) {
+		// The built-in function cannot be evaluated.
 		return nil
 	}
 
@@ -210,18 +222,17 @@ func (check *Checker) typeOfBuiltInCall(node *ast.BuiltInCall) types.Type {
 
 func (check *Checker) typeOfCall(node *ast.Call) types.Type {
 	tOperand := check.typeOf(node.X)
-	if tOperand == nil {
+
+	// The arguments are checked even if the operand is invalid.
+	tArgs := types.SkipUntyped(check.typeOfParenList(node.Args))
+
+	if types.IsInvalid(tOperand) {
 		return nil
 	}
 
 	fn := types.AsFunc(tOperand)
 	if fn == nil {
-		check.errorf(node.X, "expression is not a function")
-		return nil
-	}
-
-	tArgs := types.SkipUntyped(check.typeOfParenList(node.Args))
-	if tArgs == nil {
+		check.errorf(node.X, report.CodeNotCallable, "expression is not a function")
 		return nil
 	}
 
@@ -232,7 +243,7 @@ func (check *Checker) typeOfCall(node *ast.Call) types.Type {
 			n = node.Args.Exprs[idx]
 		}
 
-		check.errorf(n, err.Error())
+		check.errorf(n, report.CodeInvalidArguments, err.Error())
 		return nil
 	}
 
@@ -241,39 +252,36 @@ func (check *Checker) typeOfCall(node *ast.Call) types.Type {
 
 func (check *Checker) typeOfIndex(node *ast.Index) types.Type {
 	t := check.typeOf(node.X)
-	if t == nil {
-		return nil
-	}
 
-	if t.Equals(types.Unit) {
-		check.errorf(node.X, "expession is of type (unit) and cannot be indexed")
+	if len(node.Args.Exprs) != 1 {
+		check.errorf(node.Args.ExprList, report.CodeInvalidIndex, "expected 1 argument")
 		return nil
 	}
 
-	if len(node.Args.Exprs) != 1 {
-		check.errorf(node.Args.ExprList, "expected 1 argument")
+	tIndex := check.typeOf(node.Args.Exprs[0])
+	if types.IsInvalid(t) || types.IsInvalid(tIndex) {
 		return nil
 	}
 
-	tIndex := check.typeOf(node.Args.Exprs[0])
-	if tIndex == nil {
+	if t.Equals(types.Unit) {
+		check.errorf(node.X, report.CodeInvalidIndex, "expession is of type (unit) and cannot be indexed")
 		return nil
 	}
 
 	if array := types.AsArray(t); array != nil {
 		if !tIndex.Equals(types.I32) {
-			check.errorf(node.Args.Exprs[0], "expected type (i32) for index, got (%s) instead", tIndex)
+			check.errorf(node.Args.Exprs[0], report.CodeInvalidIndex, "expected type (i32) for index, got (%s) instead", tIndex)
 			return nil
 		}
 		if !check.assignable(node.X) {
-			check.errorf(node.X, "expression cannot be indexed")
+			check.errorf(node.X, report.CodeInvalidIndex, "expression cannot be indexed")
 			return nil
 		}
 		return array.ElemType()
 	} else if tuple := types.AsTuple(t); tuple != nil {
 		value := check.valueOf(node.Args.Exprs[0])
 		if value == nil || value.Value == nil || value.Value.Kind() != constant.Int {
-			check.errorf(node.Args.Exprs[0], "expected compile-time integer")
+			check.errorf(node.Args.Exprs[0], report.CodeInvalidIndex, "expected compile-time integer")
 			return nil
 		}
 
@@ -281,52 +289,59 @@ func (check *Checker) typeOfIndex(node *ast.Index) types.Type {
 		tupleLen := big.NewInt(int64(tuple.Len() - 1))
 
 		if index.Sign() == -1 || index.Cmp(tupleLen) == 1 {
-			check.errorf(node.Args.Exprs[0], "index must be in range 0..%d", tuple.Len()-1)
+			check.errorf(node.Args.Exprs[0], report.CodeInvalidIndex, "index must be in range 0..%d", tuple.Len()-1)
 			return nil
 		}
 
 		return tuple.Types()[index.Int64()]
 	}
 
-	check.errorf(node.X, "expression is not an array or tuple")
+	check.errorf(node.X, report.CodeInvalidIndex, "expression is not an array or tuple")
 	return nil
 }
 
 func (check *Checker) typeOfArrayType(node *ast.ArrayType) types.Type {
 	if len(node.Args.Exprs) == 0 {
-		check.errorf(node.Args, "slices are not implemented")
+		check.errorf(node.Args, report.CodeNotImplemented, "slices are not implemented")
 		return nil
 	}
 
 	if len(node.Args.Exprs) > 1 {
-		check.errorf(node.Args, "expected 1 argument")
+		check.errorf(node.Args, report.CodeInvalidArraySize, "expected 1 argument")
 		return nil
 	}
 
 	value := check.valueOf(node.Args.Exprs[0])
 	if value == nil {
-		check.errorf(node.Args.Exprs[0], "array size cannot be infered")
+		if !types.IsInvalid(check.typeOf(node.Args.Exprs[0])) {
+			check.errorf(node.Args.Exprs[0], report.CodeInvalidArraySize, "array size cannot be infered")
+		}
+
+		return nil
+	}
+
+	if types.IsInvalid(value.Type) {
 		return nil
 	}
 
 	intValue := constant.AsInt(value.Value)
 	if intValue == nil {
-		check.errorf(node.Args.Exprs[0], "expected integer value for array size")
+		check.errorf(node.Args.Exprs[0], report.CodeInvalidArraySize, "expected integer value for array size")
 		return nil
 	}
 
 	if intValue.Sign() == -1 || intValue.Int64() > math.MaxInt {
-		check.errorf(node.Args.Exprs[0], "size must be in range 0..9223372036854775807")
+		check.errorf(node.Args.Exprs[0], report.CodeInvalidArraySize, "size must be in range 0..9223372036854775807")
 		return nil
 	}
 
 	elemType := check.typeOf(node.X)
-	if elemType == nil {
+	if types.IsInvalid(elemType) {
 		return nil
 	}
 
 	if !types.IsTypeDesc(elemType) {
-		check.errorf(node.X, "expected type, got (%s)", elemType)
+		check.errorf(node.X, report.CodeExpectedType, "expected type, got (%s)", elemType)
 		return nil
 	}
 
@@ -337,20 +352,16 @@ func (check *Checker) typeOfArrayType(node *ast.ArrayType) types.Type {
 
 func (check *Checker) typeOfSignature(node *ast.Signature) types.Type {
 	tParams := check.typeOfParenList(node.Params)
-	if tParams == nil {
-		return nil
-	}
-
 	tResult := types.Unit
 
 	if node.Result != nil {
 		tActualResult := check.typeOf(node.Result)
-		if tActualResult == nil {
+		if types.IsInvalid(tActualResult) {
 			return nil
 		}
 
 		if !types.IsTypeDesc(tActualResult) {
-			check.errorf(node.Result, "expected type, got (%s) instead", tActualResult)
+			check.errorf(node.Result, report.CodeExpectedType, "expected type, got (%s) instead", tActualResult)
 			return nil
 		}
 
@@ -367,30 +378,39 @@ func (check *Checker) typeOfMemberAccess(node *ast.MemberAccess) types.Type {
 			if member, _ := node.Selector.(*ast.Ident); member != nil {
 				if sym, _ := m.Scope.Lookup(member.Name); sym != nil {
 					if sym.Type() == nil {
-						check.errorf(node.Selector, "expression has no type")
+						check.errorf(node.Selector, report.CodeExpectedValue, "expression has no type")
+						return nil
 					}
+					check.newUse(member, sym)
 					return sym.Type()
 				}
-				check.errorf(
+				err := NewErrorf(
 					node.Selector,
 					"identifier `%s` is not defined in the module `%s`",
 					member,
 					m.Name(),
 				)
+				err.Code = report.CodeUndefinedName
+				err.Fixes = suggestFix(member, member.Name, m.Scope.Names())
+				check.addError(err)
 				return nil
 			}
-			check.errorf(node.Selector, "expected identifier in module member access expression")
+			check.errorf(node.Selector, report.CodeUnknownMember, "expected identifier in module member access expression")
 			return nil
 		}
 	}
 
 	tOperand := check.typeOf(node.X)
-	if tOperand == nil {
+	if types.IsInvalid(tOperand) {
 		return nil
 	}
 
 	// TODO get symbol of the type.
 	if typedesc := types.AsTypeDesc(tOperand); typedesc != nil {
+		if types.IsInvalid(typedesc.Base()) {
+			return nil
+		}
+
 		switch t := typedesc.Base().Underlying().(type) {
 		case *types.Struct:
 			return check.structInit(node, typedesc)
@@ -404,24 +424,25 @@ func (check *Checker) typeOfMemberAccess(node *ast.MemberAccess) types.Type {
 		return check.structMember(node.X, node.Selector, tStruct)
 	}
 
+	check.errorf(node.X, report.CodeUnknownMember, "expression of type (%s) has no members", tOperand)
 	return nil
 }
 
 func (check *Checker) typeOfSafeMemberAccess(node *ast.SafeMemberAccess) types.Type {
 	tOperand := check.typeOf(node.X)
-	if tOperand == nil {
+	if types.IsInvalid(tOperand) {
 		return nil
 	}
 
 	tPtr := types.AsRef(tOperand)
 	if tPtr == nil {
-		check.errorf(node.X, "expected pointer to struct")
+		check.errorf(node.X, report.CodeUnknownMember, "expected pointer to struct")
 		return nil
 	}
 
 	tStruct := types.AsStruct(tPtr.Base())
 	if tStruct == nil {
-		check.errorf(node.X, "expected pointer to struct")
+		check.errorf(node.X, report.CodeUnknownMember, "expected pointer to struct")
 		return nil
 	}
 
@@ -430,7 +451,7 @@ func (check *Checker) typeOfSafeMemberAccess(node *ast.SafeMemberAccess) types.T
 
 func (check *Checker) typeOfPrefixOp(node *ast.PrefixOp) types.Type {
 	tOperand := check.typeOf(node.X)
-	if tOperand == nil {
+	if types.IsInvalid(tOperand) {
 		return nil
 	}
 
@@ -439,12 +460,14 @@ func (check *Checker) typeOfPrefixOp(node *ast.PrefixOp) types.Type {
 
 func (check *Checker) typeOfInfixOp(node *ast.InfixOp) types.Type {
 	tOperandX := check.typeOf(node.X)
-	if tOperandX == nil {
+	tOperandY := check.typeOf(node.Y)
+
+	if node.Opr.Kind.IsRange() {
+		check.errorf(node, report.CodeInvalidRange, "range can only be used in a 'for' loop")
 		return nil
 	}
 
-	tOperandY := check.typeOf(node.Y)
-	if tOperandY == nil {
+	if types.IsInvalid(tOperandX) || types.IsInvalid(tOperandY) {
 		return nil
 	}
 
@@ -452,17 +475,20 @@ func (check *Checker) typeOfInfixOp(node *ast.InfixOp) types.Type {
 }
 
 func (check *Checker) typeOfPostfixOp(node *ast.PostfixOp) types.Type {
-	check.errorf(node, "postfix operators are not supported")
+	check.errorf(node, report.CodeNotImplemented, "postfix operators are not supported")
 	return nil
 }
 
 func (check *Checker) typeOfBracketList(node *ast.BracketList) types.Type {
 	var elemType types.Type
 
+	isValid := true
+
 	for _, expr := range node.Exprs {
 		t := check.typeOf(expr)
-		if t == nil {
-			return nil
+		if types.IsInvalid(t) {
+			isValid = false
+			continue
 		}
 
 		if elemType == nil {
@@ -471,11 +497,15 @@ func (check *Checker) typeOfBracketList(node *ast.BracketList) types.Type {
 		}
 
 		if !t.Equals(elemType) {
-			check.errorf(expr, "expected type (%s) for element, got (%s) instead", elemType, t)
-			return nil
+			check.errorf(expr, report.CodeTypeMismatch, "expected type (%s) for element, got (%s) instead", elemType, t)
+			isValid = false
 		}
 	}
 
+	if !isValid {
+		return nil
+	}
+
 	size := len(node.Exprs)
 	return types.NewArray(size, elemType)
 }
@@ -490,9 +520,6 @@ func (check *Checker) typeOfParenList(node *ast.ParenList) types.Type {
 	// isTypeDescTuple := false
 
 	t := check.typeOf(node.Exprs[0])
-	if t == nil {
-		return nil
-	}
 
 	// if types.IsTypeDesc(t) {
 	// 	isTypeDescTuple = true
@@ -505,9 +532,6 @@ func (check *Checker) typeOfParenList(node *ast.ParenList) types.Type {
 
 	for _, expr := range node.Exprs[1:] {
 		t := check.typeOf(expr)
-		if t == nil {
-			return nil
-		}
 
 		// if isTypeDescTuple {
 		// 	if !types.IsTypeDesc(t) {
@@ -553,11 +577,11 @@ func (check *Checker) typeOfCurlyList(node *ast.CurlyList) types.Type {
 
 func (check *Checker) typeOfIf(node *ast.If) types.Type {
 	tCondition := check.typeOf(node.Cond)
-	// Don't return if 'tCondition == nil', check the body.
 
-	if tCondition != nil && !tCondition.Equals(types.Bool) {
+	if !tCondition.Equals(types.Bool) {
 		check.errorf(
 			node.Cond,
+			report.CodeNonBoolCondition,
 			"expected type (bool) for condition, got (%s) instead",
 			tCondition,
 		)
@@ -565,11 +589,13 @@ func (check *Checker) typeOfIf(node *ast.If) types.Type {
 	}
 
 	tBody := check.typeOf(node.Body)
-	if tBody == nil {
-		return nil
-	}
 
 	if node.Else != nil {
+		if isTerminating(node.Body) {
+			// The type of the branch that returns doesn't matter.
+			return check.typeOf(node.Else.Body)
+		}
+
 		if !check.typeOfElse(node.Else, tBody) {
 			return nil
 		}
@@ -580,12 +606,8 @@ func (check *Checker) typeOfIf(node *ast.If) types.Type {
 
 func (check *Checker) typeOfElse(node *ast.Else, tExpected types.Type) bool {
 	tBody := check.typeOf(node.Body)
-	if tBody == nil {
-		return false
-	}
-
 	tTypedBody := types.SkipUntyped(tBody)
-	if !tBody.Equals(tExpected) && !tTypedBody.Equals(tExpected) {
+	if !tBody.Equals(tExpected) && !tTypedBody.Equals(tExpected) && !isTerminating(node.Body) {
 		// Find the last node in the body for better error message.
 		lastNode := ast.Node(node.Body)
 
@@ -599,6 +621,7 @@ func (check *Checker) typeOfElse(node *ast.Else, tExpected types.Type) bool {
 
 		check.errorf(
 			lastNode,
+			report.CodeBranchMismatch,
 			"all branches must have the same type with first branch (%s), got (%s) instead",
 			tExpected,
 			tBody,
@@ -609,26 +632,233 @@ func (check *Checker) typeOfElse(node *ast.Else, tExpected types.Type) bool {
 	return true
 }
 
+func (check *Checker) typeOfReturn(node *ast.Return) types.Type {
+	tValue := types.Type(types.Unit)
+
+	if node.X != nil {
+		check.allowMatch(node.X)
+		tValue = check.typeOf(node.X)
+	}
+
+	if check.fn == nil {
+		check.errorf(node, report.CodeInvalidReturn, "'return' outside of a function")
+		return types.Unit
+	}
+
+	tResult := check.fn.Type().(*types.Func).Result()
+
+	if !tValue.Equals(tResult) {
+		n := ast.Node(node)
+
+		if node.X != nil {
+			n = node.X
+		}
+
+		check.errorf(
+			n,
+			report.CodeTypeMismatch,
+			"expected expression of type '%s' for function result, got '%s' instead",
+			tResult,
+			tValue,
+		)
+	}
+
+	// 'return' has no value, the result is checked above.
+	return types.Unit
+}
+
+// Reports whether the statement always returns from the function,
+// i.e. it is 'return' or a block, 'if' or 'match' with all branches
+// that end with 'return'.
+func isTerminating(node ast.Node) bool {
+	switch node := node.(type) {
+	case *ast.Return:
+		return true
+
+	case *ast.CurlyList:
+		return len(node.Nodes) > 0 && isTerminating(node.Nodes[len(node.Nodes)-1])
+
+	case *ast.If:
+		return node.Else != nil && isTerminating(node.Body) && isTerminating(node.Else.Body)
+
+	case *ast.Match:
+		// Non-exhaustive match is reported by the checker.
+		for _, arm := range node.Body.Nodes {
+			if !isTerminating(arm.(*ast.MatchArm).X) {
+				return false
+			}
+		}
+
+		return len(node.Body.Nodes) > 0
+
+	default:
+		return false
+	}
+}
+
 func (check *Checker) typeOfWhile(node *ast.While) types.Type {
+	check.checkLoopLabel(node.Label)
+
 	tCond := check.typeOf(node.Cond)
-	if tCond == nil {
-		return nil
-	}
 
 	if !tCond.Equals(types.Bool) {
-		check.errorf(node.Cond, "expected type 'bool' for condition, got (%s) instead", tCond)
+		check.errorf(node.Cond, report.CodeNonBoolCondition, "expected type 'bool' for condition, got (%s) instead", tCond)
 		// Don't return, check the body.
 	}
 
+	check.loops = append(check.loops, node.Label)
 	tBody := check.typeOf(node.Body)
-	if tBody == nil {
+	check.loops = check.loops[:len(check.loops)-1]
+
+	if !tBody.Equals(types.Unit) {
+		check.errorf(node.Body, report.CodeTypeMismatch, "while loop body must have no type, but got (%s)", tBody)
 		return nil
 	}
 
+	return types.Unit
+}
+
+func (check *Checker) typeOfFor(node *ast.For) types.Type {
+	check.checkLoopLabel(node.Label)
+
+	// The iterated expression is checked in the outer scope.
+	tValue := types.Type(types.Invalid)
+
+	if x, _ := node.X.(*ast.InfixOp); x != nil && x.Opr.Kind.IsRange() {
+		if node.Index != nil {
+			check.errorf(node.Index, report.CodeInvalidRange, "range has no index, use 'for %s in ...' instead", node.Value.Name)
+		}
+
+		if t := check.typeOfRange(x); t != nil {
+			tValue = t
+		}
+	} else if t := check.typeOf(node.X); !types.IsInvalid(t) {
+		if array := types.AsArray(t); array != nil {
+			if !check.assignable(node.X) {
+				check.errorf(node.X, report.CodeNotIterable, "array cannot be iterated, store it in a variable first")
+			}
+
+			tValue = types.SkipUntyped(array.ElemType())
+		} else {
+			check.errorf(node.X, report.CodeNotIterable, "expression of type (%s) cannot be iterated", t)
+		}
+	}
+
+	// The loop variables are visible only in the body.
+	defer check.setScope(check.scope)
+	check.scope = NewScope(check.scope, "block")
+
+	if node.Index != nil {
+		check.defineLoopVar(node.Index, types.I32)
+	}
+
+	check.defineLoopVar(node.Value, tValue)
+
+	check.loops = append(check.loops, node.Label)
+	tBody := check.typeOf(node.Body)
+	check.loops = check.loops[:len(check.loops)-1]
+
 	if !tBody.Equals(types.Unit) {
-		check.errorf(node.Body, "while loop body must have no type, but got (%s)", tBody)
+		check.errorf(node.Body, report.CodeTypeMismatch, "for loop body must have no type, but got (%s)", tBody)
 		return nil
 	}
 
 	return types.Unit
 }
+
+// Returns the type of the range bounds, i.e. the type of the loop
+// variable. Bounds must be integers of the same type.
+func (check *Checker) typeOfRange(node *ast.InfixOp) types.Type {
+	tStart := check.typeOf(node.X)
+	tEnd := check.typeOf(node.Y)
+
+	if types.IsInvalid(tStart) || types.IsInvalid(tEnd) {
+		return nil
+	}
+
+	if !tEnd.Equals(tStart) && !types.SkipUntyped(tEnd).Equals(types.SkipUntyped(tStart)) {
+		check.errorf(node, report.CodeTypeMismatch, "type mismatch (%s and %s)", tStart, tEnd)
+		return nil
+	}
+
+	t := tStart
+	if types.IsUntyped(t) {
+		t = tEnd
+	}
+
+	t = types.SkipUntyped(t)
+
+	if !types.IsInteger(t) {
+		check.errorf(node, report.CodeInvalidRange, "expected integer bounds of the range, got (%s) instead", t)
+		return nil
+	}
+
+	check.setType(node.X, t)
+	check.setType(node.Y, t)
+	return t
+}
+
+func (check *Checker) defineLoopVar(name *ast.Ident, t types.Type) {
+	if name.Name == "_" {
+		return
+	}
+
+	sym := NewVar(check.scope, t, nil, name)
+
+	if defined := check.scope.Define(sym); defined != nil {
+		check.addError(errorAlreadyDefined(sym.Ident(), defined.Ident()))
+		return
+	}
+
+	check.newDef(name, sym)
+}
+
+// Reports the label that is already used by an enclosing loop.
+func (check *Checker) checkLoopLabel(label *ast.Ident) {
+	if label == nil {
+		return
+	}
+
+	if enclosing := check.loopOf(label.Name); enclosing != nil {
+		err := NewErrorf(label, "label '%s' is already used by an enclosing loop", label.Name)
+		err.Code = report.CodeAlreadyDefined
+		err.Notes = []*Error{NewError(enclosing, "enclosing loop was declared here")}
+		check.addError(err)
+		// Check the loop anyway, the inner label is used.
+	}
+}
+
+// Checks that 'break' or 'continue' is placed inside of a loop. If the
+// label is specified, it must name one of the enclosing loops.
+func (check *Checker) checkLoopJump(node ast.Node, keyword string, label *ast.Ident) {
+	if len(check.loops) == 0 {
+		check.errorf(node, report.CodeOutsideLoop, "'%s' outside of a loop", keyword)
+		return
+	}
+
+	if label != nil && check.loopOf(label.Name) == nil {
+		labels := []string{}
+
+		for _, loop := range check.loops {
+			if loop != nil {
+				labels = append(labels, loop.Name)
+			}
+		}
+
+		err := NewErrorf(label, "label '%s' doesn't name an enclosing loop", label.Name)
+		err.Code = report.CodeUndefinedLabel
+		err.Fixes = suggestFix(label, label.Name, labels)
+		check.addError(err)
+	}
+}
+
+// Returns the label of the innermost enclosing loop with the name or nil.
+func (check *Checker) loopOf(label string) *ast.Ident {
+	for i := len(check.loops) - 1; i >= 0; i-- {
+		if loop := check.loops[i]; loop != nil && loop.Name == label {
+			return loop
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/saffage/jet/internal/report"
+)
+
+func TestReturn(t *testing.T) {
+	expectLines(t, `func f() i32 {
+	return
+}
+
+func g() {
+	return 1
+}
+
+func h(a bool) i32 {
+	if a {
+		return 1
+	} else {
+		2
+	}
+}
+
+func main() {}
+`, 2, 6)
+}
+
+func TestLabeledLoops(t *testing.T) {
+	expectCodes(t, `func main() {
+	break
+
+	outer: while true {
+		outer: while true {
+			break outr
+		}
+
+		while true {
+			continue outer
+		}
+	}
+
+	inner: while true {}
+	while true {
+		continue inner
+	}
+}
+`,
+		report.CodeOutsideLoop,
+		report.CodeAlreadyDefined,
+		report.CodeUndefinedLabel,
+		report.CodeUndefinedLabel,
+	)
+}
+
+func TestForLoops(t *testing.T) {
+	expectCodes(t, `func main() {
+	var arr [3]i32 = [1, 2, 3]
+	var n u8 = 4
+
+	for i, x in 0..<3 {}
+	for x in 0..<n {}
+	for x in 0.5..2.5 {}
+	for x in 10 {}
+	var r = 0..1
+
+	for i, x in arr {
+		x = i
+	}
+	@print(x)
+}
+`,
+		report.CodeInvalidRange,
+		report.CodeInvalidRange,
+		report.CodeNotIterable,
+		report.CodeInvalidRange,
+		report.CodeUndefinedName,
+	)
+}
@@ -60,27 +60,33 @@ func (check *Checker) valueOfInternal(expr ast.Node) *TypedValue {
 		return &TypedValue{type_, value}
 
 	case *ast.Ident:
-		if sym := check.symbolOf(node); sym != nil {
-			if _const, _ := sym.(*Const); _const != nil {
-				return _const.value
-			}
+		// Undefined identifiers are reported by [Checker.typeOf].
+		if _const, _ := check.symbolOf(node).(*Const); _const != nil && _const.value.Value != nil {
+			return _const.value
+		}
 
+	case *ast.InfixOp:
+		if node.Opr.Kind.IsRange() {
+			// Ranges have no value, reported by [Checker.typeOf].
 			return nil
 		}
 
-		check.errorf(node, "identifier is undefined")
-
-	case *ast.InfixOp:
 		x := check.valueOf(node.X)
 		y := check.valueOf(node.Y)
 
-		if x == nil || y == nil {
+		if x == nil || y == nil || x.Value == nil || y.Value == nil {
 			return nil
 		}
 
 		t := check.infix(node, x.Type, y.Type)
 		if t == nil {
-			return nil
+			// Already reported, don't check the operation again.
+			return &TypedValue{types.Invalid, nil}
+		}
+
+		if t == types.Unit {
+			// Assignment to a constant, already reported.
+			return &TypedValue{t, nil}
 		}
 
 		if x.Value.Kind() == y.Value.Kind() {
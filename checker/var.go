@@ -10,12 +10,13 @@ import (
 type Var struct {
 	owner    *Scope
 	t        types.Type
-	node     *ast.Binding
+	node     *ast.Binding // Nil for the variables of the 'for' loop.
 	name     *ast.Ident
 	value    ast.Node // TODO move somewhere else.
 	isParam  bool
 	isField  bool
 	isGlobal bool
+	isVal    bool
 }
 
 func NewVar(owner *Scope, t types.Type, node *ast.Binding, name *ast.Ident) *Var {
@@ -33,25 +34,35 @@ func (v *Var) Owner() *Scope     { return v.owner }
 func (v *Var) Type() types.Type  { return v.t }
 func (v *Var) Name() string      { return v.name.Name }
 func (v *Var) Ident() *ast.Ident { return v.name }
-func (v *Var) Node() ast.Node    { return v.node }
 func (v *Var) Value() ast.Node   { return v.value }
 func (v *Var) IsLocal() bool     { return !v.isParam && !v.isField && !v.isGlobal }
 func (v *Var) IsParam() bool     { return v.isParam }
 func (v *Var) IsField() bool     { return v.isField }
 func (v *Var) IsGlobal() bool    { return v.isGlobal }
+func (v *Var) IsVal() bool       { return v.isVal }
+
+func (v *Var) Node() ast.Node {
+	if v.node == nil {
+		return nil
+	}
+	return v.node
+}
 
 func (check *Checker) resolveVarDecl(node *ast.VarDecl) {
 	if node.Binding.Name.Name == "_" {
-		check.errorf(node.Binding.Name, "attempt to declare an empty identifier")
+		check.errorf(node.Binding.Name, report.CodeInvalidDecl, "attempt to declare an empty identifier")
 		return
 	}
 
-	// 'tValue' can be nil.
-	tValue, ok := check.resolveVarValue(node.Value)
-	if !ok {
-		return
+	if node.IsVal && node.Value == nil {
+		check.errorf(node.Binding, report.CodeInvalidDecl, "'val' must be initialized with a value")
+		// The variable is defined without the value.
 	}
 
+	// 'tValue' can be nil.
+	check.allowMatch(node.Value)
+	tValue := check.resolveVarValue(node.Value)
+
 	// 'tType' cannot be nil.
 	tType := check.resolveVarType(node.Binding.Type, tValue)
 	if tType == nil {
@@ -67,11 +78,12 @@ func (check *Checker) resolveVarDecl(node *ast.VarDecl) {
 	if tValue != nil && !tValue.Equals(tType) {
 		check.errorf(
 			node.Value,
+			report.CodeTypeMismatch,
 			"type mismatch, expected '%s', got '%s'",
 			tType,
 			tValue,
 		)
-		return
+		// The variable is defined with the specified type.
 	}
 
 	tType = types.SkipUntyped(tType)
@@ -87,6 +99,7 @@ func (check *Checker) resolveVarDecl(node *ast.VarDecl) {
 	sym := NewVar(check.scope, tType, node.Binding, node.Binding.Name)
 	sym.value = node.Value
 	sym.isGlobal = sym.owner == check.module.Scope
+	sym.isVal = node.IsVal
 
 	if defined := check.scope.Define(sym); defined != nil {
 		check.addError(errorAlreadyDefined(sym.Ident(), defined.Ident()))
@@ -96,32 +109,33 @@ func (check *Checker) resolveVarDecl(node *ast.VarDecl) {
 	check.newDef(node.Binding.Name, sym)
 }
 
-func (check *Checker) resolveVarValue(value ast.Node) (types.Type, bool) {
-	if value != nil {
-		t := check.typeOf(value)
-		if t == nil {
-			return nil, false
-		}
+// Returns the type of the value or nil if the value is not specified.
+func (check *Checker) resolveVarValue(value ast.Node) types.Type {
+	if value == nil {
+		return nil
+	}
 
-		if types.IsTypeDesc(t) {
-			check.errorf(value, "expected value, got type '%s' instead", t)
-			return nil, false
-		}
+	t := check.typeOf(value)
 
-		return t, true
+	if types.IsTypeDesc(t) {
+		check.errorf(value, report.CodeExpectedValue, "expected value, got type '%s' instead", t)
+		return types.Invalid
 	}
 
-	return nil, true
+	return t
 }
 
+// Returns the type specified by the expression or the type of the
+// value if the expression is nil. If the expression is not a type,
+// the result is [types.Invalid].
 func (check *Checker) resolveVarType(typeExpr ast.Node, value types.Type) types.Type {
 	if typeExpr == nil {
 		return value
 	}
 
 	t := check.typeOf(typeExpr)
-	if t == nil {
-		return value
+	if types.IsInvalid(t) {
+		return types.Invalid
 	}
 
 	typedesc := types.AsTypeDesc(t)
@@ -132,9 +146,54 @@ func (check *Checker) resolveVarType(typeExpr ast.Node, value types.Type) types.
 	}
 
 	if typedesc == nil {
-		check.errorf(typeExpr, "expression is not a type")
-		return nil
+		check.errorf(typeExpr, report.CodeExpectedType, "expression is not a type")
+		return types.Invalid
 	}
 
 	return typedesc.Base()
 }
+
+// Reports whether the location can be mutated, i.e. it is assignable and
+// isn't a part of the variable declared with 'val'.
+func (check *Checker) mutable(node ast.Node) bool {
+	if !check.assignable(node) {
+		check.errorf(node, report.CodeNotAssignable, "expression cannot be assigned")
+		return false
+	}
+
+	if sym := check.immutableVarOf(node); sym != nil {
+		check.addError(errorImmutable(node, sym))
+		return false
+	}
+
+	return true
+}
+
+// Returns the variable declared with 'val' that contains the location,
+// e.g. 'x' for 'x.a[0]', or nil if the location is mutable. Locations
+// behind a pointer are mutable.
+func (check *Checker) immutableVarOf(node ast.Node) *Var {
+	switch node := node.(type) {
+	case *ast.Ident:
+		if sym, _ := check.symbolOf(node).(*Var); sym != nil && sym.isVal {
+			return sym
+		}
+
+	case *ast.MemberAccess:
+		return check.immutableVarOf(node.X)
+
+	case *ast.Index:
+		return check.immutableVarOf(node.X)
+	}
+
+	return nil
+}
+
+func errorImmutable(node ast.Node, sym *Var) *Error {
+	err := NewErrorf(node, "cannot mutate '%s', it is declared with 'val'", sym.Name())
+	err.Code = report.CodeImmutable
+	err.Notes = []*Error{
+		NewError(sym.Ident(), "declared here, use 'var' to make it mutable"),
+	}
+	return err
+}
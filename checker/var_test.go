@@ -0,0 +1,49 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/saffage/jet/internal/report"
+)
+
+func TestVal(t *testing.T) {
+	errs := expectCodes(t, `struct P {
+	x i32
+	arr [2]i32
+}
+
+val G = 1
+
+func main() {
+	val a = 1
+	val b i32
+	val p = P.{ x = 1; arr = [2, 3] }
+	val arr [2]i32 = [1, 2]
+
+	a = 2
+	a += 1
+	p.x = 2
+	p.arr[0] = 1
+	var ptr = &p
+	G = 2
+
+	var q = p
+	q.x = a + p.arr[1] + arr[0] + G
+	for x in arr {}
+}
+`,
+		report.CodeInvalidDecl,
+		report.CodeImmutable,
+		report.CodeImmutable,
+		report.CodeImmutable,
+		report.CodeImmutable,
+		report.CodeImmutable,
+		report.CodeImmutable,
+	)
+
+	// The note points to the declaration.
+	if notes := errs[1].(*Error).Notes; len(notes) != 1 || !strings.Contains(notes[0].Error(), "declared here") {
+		t.Errorf("expected the note with the declaration, have %v", notes)
+	}
+}
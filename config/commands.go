@@ -0,0 +1,406 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Command describes a subcommand of the compiler, e.g. `jet build`.
+type Command struct {
+	// Name of the command used on the command line.
+	Name string
+
+	// Arguments of the command displayed in the usage line.
+	UsageArgs string
+
+	// Short description displayed in the command list.
+	Short string
+
+	// Detailed description displayed by `jet help <command>`.
+	Long string
+
+	// Number of the expected positional arguments. If the value
+	// is negative, any number of arguments is allowed.
+	//
+	// NOTE: arguments of the program passed to `jet run` are
+	// stored in [RunArgs] and are not counted.
+	NArgs int
+
+	// If set, the file argument can be omitted when the current
+	// directory belongs to a project (see [Project]).
+	ProjectAware bool
+
+	// Registers the flags of the command.
+	setFlags func(flagSet *flag.FlagSet)
+
+	// Command is not implemented yet.
+	NotImplemented bool
+}
+
+// Commands of the compiler in the order they are displayed by `jet help`.
+var Commands = []*Command{
+	{
+		Name:         "check",
+		UsageArgs:    "[flags] [file.jet]",
+		Short:        "Check a module for errors",
+		Long:         "Parses and type-checks the specified module and all of its imports.",
+		NArgs:        1,
+		ProjectAware: true,
+		setFlags:     addCheckFlags,
+	},
+	{
+		Name:      "build",
+		UsageArgs: "[flags] [file.jet]",
+		Short:     "Compile a module into an executable",
+		Long: "Checks the specified module, generates C code into the '.jet' directory\n" +
+			"next to it and compiles the generated code with the system C compiler.",
+		NArgs:        1,
+		ProjectAware: true,
+		setFlags: func(flagSet *flag.FlagSet) {
+			addCheckFlags(flagSet)
+			addCCFlags(flagSet)
+			flagSet.StringVar(
+				&FlagOutput,
+				"o",
+				"",
+				"Path to the output executable (defaults to '.jet/<module>')",
+			)
+		},
+	},
+	{
+		Name:      "run",
+		UsageArgs: "[flags] [file.jet] [--] [arguments]",
+		Short:     "Compile and run a program",
+		Long: "Builds the specified module into a temporary directory and runs it.\n" +
+			"Arguments after the file name are passed to the program. The exit code\n" +
+			"of the compiler is the exit code of the program.",
+		NArgs:        1,
+		ProjectAware: true,
+		setFlags: func(flagSet *flag.FlagSet) {
+			addCommonFlags(flagSet)
+			addCCFlags(flagSet)
+		},
+	},
+	{
+		Name:      "ast",
+		UsageArgs: "[flags] <file.jet>",
+		Short:     "Display the AST of a module",
+		Long:      "Parses the specified module and displays its AST.",
+		NArgs:     1,
+		setFlags: func(flagSet *flag.FlagSet) {
+			addCommonFlags(flagSet)
+			flagSet.BoolVar(
+				&FlagTraceParser,
+				"trace_parser",
+				false,
+				"Trace parser calls (for debugging)",
+			)
+			flagSet.BoolVar(
+				&FlagJSON,
+				"json",
+				false,
+				"Output the AST in JSON format",
+			)
+		},
+	},
+	{
+		Name:      "tokens",
+		UsageArgs: "[flags] <file.jet>",
+		Short:     "Display the tokens of a module",
+		Long:      "Scans the specified module and displays its tokens.",
+		NArgs:     1,
+		setFlags: func(flagSet *flag.FlagSet) {
+			addCommonFlags(flagSet)
+			flagSet.BoolVar(
+				&FlagJSON,
+				"json",
+				false,
+				"Output the tokens in JSON format",
+			)
+		},
+	},
+	{
+		Name:      "emit-c",
+		UsageArgs: "[flags] [file.jet]",
+		Short:     "Generate C code from a module",
+		Long: "Checks the specified module and generates C code for it and all of\n" +
+			"its imports into the '.jet' directory next to it.",
+		NArgs:        1,
+		ProjectAware: true,
+		setFlags:     addCheckFlags,
+	},
+	{
+		Name:      "fmt",
+		UsageArgs: "[flags] [path ...]",
+		Short:     "Format Jet source code",
+		Long: "Formats the specified files in place. Directories are searched for\n" +
+			"'.jet' files recursively. If no path is specified, the current\n" +
+			"directory is used.\n\n" +
+			"With '-check' or '-diff' the files are not changed and the exit code\n" +
+			"is 1 if any of them is not formatted.",
+		NArgs: -1,
+		setFlags: func(flagSet *flag.FlagSet) {
+			addCommonFlags(flagSet)
+			flagSet.BoolVar(
+				&FlagCheck,
+				"check",
+				false,
+				"List the files whose formatting differs instead of rewriting them",
+			)
+			flagSet.BoolVar(
+				&FlagDiff,
+				"diff",
+				false,
+				"Display the changes as a unified diff instead of rewriting the files",
+			)
+		},
+	},
+	{
+		Name:      "doc",
+		UsageArgs: "[flags] [file.jet]",
+		Short:     "Generate documentation for a module",
+		Long: "Checks the specified module and generates documentation for its\n" +
+			"declarations from the doc comments. Doc comments start with '##' and\n" +
+			"are placed on the lines right before the declaration, struct field\n" +
+			"or enum member. The documentation is written in Markdown format to\n" +
+			"the standard output unless '-html' or '-o' is specified.",
+		NArgs:        1,
+		ProjectAware: true,
+		setFlags: func(flagSet *flag.FlagSet) {
+			addCommonFlags(flagSet)
+			flagSet.BoolVar(
+				&FlagHTML,
+				"html",
+				false,
+				"Generate a static HTML page instead of Markdown",
+			)
+			flagSet.StringVar(
+				&FlagOutput,
+				"o",
+				"",
+				"Path to the output file (defaults to the standard output)",
+			)
+		},
+	},
+	{
+		Name:      "test",
+		UsageArgs: "[flags] [file.jet]",
+		Short:     "Run the tests of a module",
+		Long: "Builds and runs the tests declared in the specified module. Tests are\n" +
+			"functions with attribute @(Test) that have no parameters and no result.\n" +
+			"Each test is run in a separate process, so a failed assertion doesn't\n" +
+			"stop the remaining tests. The exit code is 1 if any of the tests failed.",
+		NArgs:        1,
+		ProjectAware: true,
+		setFlags: func(flagSet *flag.FlagSet) {
+			addCommonFlags(flagSet)
+			addCCFlags(flagSet)
+			flagSet.StringVar(
+				&FlagRun,
+				"run",
+				"",
+				"Run only the tests whose names match the regular expression",
+			)
+		},
+	},
+	{
+		Name:      "bench",
+		UsageArgs: "[flags] [file.jet]",
+		Short:     "Run the benchmarks of a module",
+		Long: "Builds and runs the benchmarks declared in the specified module.\n" +
+			"Benchmarks are functions with attribute @(Bench) that have no parameters\n" +
+			"and no result. Each benchmark is called repeatedly until it runs for at\n" +
+			"least the benchmark time, then the average time per call is reported.\n" +
+			"Pass '-cflags=-O2' to measure the optimized code.",
+		NArgs:        1,
+		ProjectAware: true,
+		setFlags: func(flagSet *flag.FlagSet) {
+			addCommonFlags(flagSet)
+			addCCFlags(flagSet)
+			flagSet.StringVar(
+				&FlagRun,
+				"run",
+				"",
+				"Run only the benchmarks whose names match the regular expression",
+			)
+			flagSet.DurationVar(
+				&FlagBenchTime,
+				"benchtime",
+				time.Second,
+				"Minimum time of running each benchmark",
+			)
+		},
+	},
+	{
+		Name:      "repl",
+		UsageArgs: "[flags]",
+		Short:     "Run the interactive REPL",
+		Long: "Runs the interactive Read-Eval-Print Loop. Declarations entered in the\n" +
+			"REPL are kept for the subsequent inputs. Constant expressions are\n" +
+			"evaluated by the compiler, other code is compiled by the C compiler\n" +
+			"and run. Type ':help' in the REPL for the list of commands.",
+		NArgs: 0,
+		setFlags: func(flagSet *flag.FlagSet) {
+			addCommonFlags(flagSet)
+			addCCFlags(flagSet)
+		},
+	},
+	{
+		Name:      "lsp",
+		UsageArgs: "[flags]",
+		Short:     "Run the language server",
+		Long: "Runs the language server that communicates over stdin and stdout\n" +
+			"using the Language Server Protocol.",
+		NArgs:    0,
+		setFlags: addCommonFlags,
+	},
+	{
+		Name:      "version",
+		UsageArgs: "[flags]",
+		Short:     "Display the compiler and core library versions",
+		Long:      "Displays the version of the compiler and the version of the core library.",
+		NArgs:     0,
+		setFlags:  addCommonFlags,
+	},
+	{
+		Name:      "explain",
+		UsageArgs: "[code]",
+		Short:     "Explain a diagnostic code",
+		Long: "Displays the detailed explanation of the diagnostic code, e.g. 'E0201',\n" +
+			"with an example of the erroneous code and the fix. Diagnostics display\n" +
+			"their code after the message. If the code is omitted, the list of all\n" +
+			"codes is displayed.",
+		NArgs:    -1,
+		setFlags: func(*flag.FlagSet) {},
+	},
+	{
+		Name:      "help",
+		UsageArgs: "[command]",
+		Short:     "Display help about a command",
+		Long:      "Displays the list of commands or the help of the specified command.",
+		NArgs:     -1,
+		setFlags:  func(*flag.FlagSet) {},
+	},
+}
+
+// Returns a command with the specified name or nil.
+func LookupCommand(name string) *Command {
+	for _, cmd := range Commands {
+		if cmd.Name == name {
+			return cmd
+		}
+	}
+
+	return nil
+}
+
+func (cmd *Command) flagSet() *flag.FlagSet {
+	flagSet := flag.NewFlagSet("jet "+cmd.Name, flag.ContinueOnError)
+	flagSet.SetOutput(io.Discard)
+	cmd.setFlags(flagSet)
+	return flagSet
+}
+
+// Parses the command line arguments and sets [Cmd], [Args] and
+// the flag values.
+//
+// Returns [flag.ErrHelp] if the help was requested via the '-h' flag.
+func ParseArgs(args []string) error {
+	Exe = args[0]
+
+	if len(args) < 2 {
+		Cmd = LookupCommand("help")
+		Args = nil
+		return nil
+	}
+
+	Cmd = LookupCommand(args[1])
+
+	if Cmd == nil {
+		return fmt.Errorf("unknown command '%s'; run 'jet help' for usage", args[1])
+	}
+
+	flagSet := Cmd.flagSet()
+
+	if err := flagSet.Parse(args[2:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+
+		return fmt.Errorf("%s; run 'jet help %s' for usage", err.Error(), Cmd.Name)
+	}
+
+	Args = flagSet.Args()
+
+	if Cmd.Name == "run" && len(Args) > 0 {
+		if filepath.Ext(Args[0]) == ".jet" {
+			RunArgs = Args[1:]
+			Args = Args[:1]
+		} else {
+			// Run the project, all arguments are passed to the program.
+			RunArgs = Args
+			Args = nil
+		}
+
+		if len(RunArgs) > 0 && RunArgs[0] == "--" {
+			RunArgs = RunArgs[1:]
+		}
+	}
+
+	switch {
+	case Cmd.ProjectAware && len(Args) == 0:
+		// The project manifest will be used.
+
+	case Cmd.NArgs == 1 && len(Args) == 0:
+		return fmt.Errorf("expected filename")
+
+	case Cmd.NArgs >= 0 && len(Args) != Cmd.NArgs:
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(Args[Cmd.NArgs:], " "))
+	}
+
+	return nil
+}
+
+// Prints the list of the commands.
+func PrintUsage(w io.Writer) {
+	fmt.Fprint(w, "Usage: jet <command> [arguments]\n\n")
+	fmt.Fprintln(w, "Commands:")
+
+	for _, cmd := range Commands {
+		fmt.Fprintf(w, "    %-10s %s", cmd.Name, cmd.Short)
+
+		if cmd.NotImplemented {
+			fmt.Fprint(w, " (not yet implemented)")
+		}
+
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintln(w, "\nRun 'jet help <command>' for more information about a command.")
+}
+
+// Prints the usage line, the description and the flags of the command.
+func (cmd *Command) PrintHelp(w io.Writer) {
+	fmt.Fprintf(w, "Usage: jet %s %s\n\n", cmd.Name, cmd.UsageArgs)
+	fmt.Fprintln(w, cmd.Long)
+
+	if cmd.ProjectAware {
+		fmt.Fprintf(w, "\nIf the file is omitted, the entry module of the project declared\n"+
+			"in '%s' is used (see the 'main' key).\n", ProjectFileName)
+	}
+
+	flagSet := cmd.flagSet()
+	hasFlags := false
+	flagSet.VisitAll(func(*flag.Flag) { hasFlags = true })
+
+	if hasFlags {
+		fmt.Fprintln(w, "\nFlags:")
+		flagSet.SetOutput(w)
+		flagSet.PrintDefaults()
+	}
+}
@@ -1,19 +1,43 @@
 package config
 
-var Global = New()
-
 // Must be created via the [New] function.
 type Config struct {
 	// Contains filenames indexed by their IDs.
 	//
 	// NOTE: the file on which the compiler was called always has the key [config.MainFileID].
-	Files     map[FileID]FileInfo
+	Files map[FileID]FileInfo
+
+	// Maximum number of the reported errors (0 means no limit).
+	// See [report.Limit].
 	MaxErrors int
+
+	// Project the compiled module belongs to. Nil if the module is
+	// compiled without the project manifest.
+	Project *Project
+
+	// Path to the core library.
+	LibPath string
+
+	// Trace parser calls (for debugging).
+	TraceParser bool
+
+	// Dump the checker state into '.jet/checker_state.txt'.
+	DumpCheckerState bool
+
+	nextFileID FileID
 }
 
 func New() *Config {
 	return &Config{
-		Files:     map[FileID]FileInfo{},
-		MaxErrors: 3,
+		Files:      map[FileID]FileInfo{},
+		MaxErrors:  3,
+		nextFileID: firstFreeFileID,
 	}
 }
+
+// Returns a new unique file ID.
+func (cfg *Config) NextFileID() FileID {
+	id := cfg.nextFileID
+	cfg.nextFileID++
+	return id
+}
@@ -18,11 +18,3 @@ type FileInfo struct {
 	Path string        // Path to the file.
 	Buf  *bytes.Buffer // File content.
 }
-
-func NextFileID() FileID {
-	id := fileID
-	fileID++
-	return id
-}
-
-var fileID = firstFreeFileID
@@ -1,49 +1,115 @@
 package config
 
-import "flag"
+import (
+	"flag"
+	"time"
+)
 
 // Enable debug information.
 var FlagDebug = false
 
 var FlagDumpCheckerState = false
 
-// Display a program AST of the specified Jet module and exit.
-var FlagParseAst = false
-
 // Trace parser calls (for debugging).
 var FlagTraceParser = false
 
-// Generate C source file from the specified Jet module.
-var FlagGenC = false
-
 // Specifies the path to the core library.
 var FlagCoreLibPath = ""
 
-// Non-flag command line arguments.
+// Format of the diagnostics: "text", "json" or "sarif".
+var FlagDiagnostics = "text"
+
+// File the diagnostics in JSON or SARIF format are written to. If
+// empty, they are written to stderr.
+var FlagDiagnosticsFile = ""
+
+// Maximum number of the displayed errors (0 means no limit).
+// Diagnostics in JSON or SARIF format are not limited.
+var FlagMaxErrors = 3
+
+// Output the tokens or the AST in JSON format.
+var FlagJSON = false
+
+// C compiler used to build the program. If empty, the
+// value of the `CC` environment variable is used.
+var FlagCC = ""
+
+// Additional flags passed to the C compiler.
+var FlagCFlags = ""
+
+// Additional flags passed to the C compiler when linking.
+var FlagLDFlags = ""
+
+// Path to the output executable.
+var FlagOutput = ""
+
+// Regular expression that selects the tests or benchmarks to run.
+var FlagRun = ""
+
+// Minimum time of running each benchmark.
+var FlagBenchTime = time.Second
+
+// Generate the documentation in HTML format instead of Markdown.
+var FlagHTML = false
+
+// List the files whose formatting differs instead of rewriting them.
+var FlagCheck = false
+
+// Display the formatting changes as a unified diff instead of
+// rewriting the files.
+var FlagDiff = false
+
+// Command specified by the user.
+var Cmd *Command
+
+// Non-flag command line arguments of the command.
 var Args []string
 
-var Exe string
+// Arguments passed to the program by the `run` command.
+var RunArgs []string
 
-func ParseArgs(args []string) {
-	flagSet := flag.NewFlagSet("jet", flag.ExitOnError)
+var Exe string
 
+func addCommonFlags(flagSet *flag.FlagSet) {
 	flagSet.BoolVar(
 		&FlagDebug,
 		"debug",
 		false,
 		"Enable debug information",
 	)
-	flagSet.BoolVar(
-		&FlagDumpCheckerState,
-		"dump_checker_state",
-		false,
+	flagSet.StringVar(
+		&FlagCoreLibPath,
+		"lib_path",
 		"",
+		"Specifies the path to the core library",
+	)
+	flagSet.StringVar(
+		&FlagDiagnostics,
+		"diagnostics",
+		"text",
+		"Format of the diagnostics: 'text', 'json' or 'sarif'",
+	)
+	flagSet.StringVar(
+		&FlagDiagnosticsFile,
+		"diagnostics_file",
+		"",
+		"File to write the diagnostics in JSON or SARIF format to (defaults to stderr)",
+	)
+	flagSet.IntVar(
+		&FlagMaxErrors,
+		"max_errors",
+		3,
+		"Maximum number of the displayed errors (0 means no limit), JSON and SARIF diagnostics are not limited",
 	)
+}
+
+func addCheckFlags(flagSet *flag.FlagSet) {
+	addCommonFlags(flagSet)
 	flagSet.BoolVar(
-		&FlagParseAst,
-		"parse_ast",
+		&FlagDumpCheckerState,
+		"dump_checker_state",
 		false,
-		"Display a module AST and exit",
+		"Dump the checker state into '.jet/checker_state.txt'",
 	)
 	flagSet.BoolVar(
 		&FlagTraceParser,
@@ -51,24 +117,25 @@ func ParseArgs(args []string) {
 		false,
 		"Trace parser calls (for debugging)",
 	)
-	flagSet.BoolVar(
-		&FlagGenC,
-		"gen_c",
-		false,
-		"Generate C source file from the specified Jet module",
+}
+
+func addCCFlags(flagSet *flag.FlagSet) {
+	flagSet.StringVar(
+		&FlagCC,
+		"cc",
+		"",
+		"C compiler command used to build the program, may contain arguments (defaults to $CC or 'cc')",
 	)
 	flagSet.StringVar(
-		&FlagCoreLibPath,
-		"lib_path",
+		&FlagCFlags,
+		"cflags",
 		"",
-		"Specifies the path to the core library",
+		"Additional flags passed to the C compiler",
+	)
+	flagSet.StringVar(
+		&FlagLDFlags,
+		"ldflags",
+		"",
+		"Additional flags passed to the C compiler when linking",
 	)
-
-	if err := flagSet.Parse(args[1:]); err != nil {
-		// Must be unreachable due to specified error handling.
-		panic(err)
-	}
-
-	Args = flagSet.Args()
-	Exe = args[0]
 }
@@ -0,0 +1,288 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Name of the project manifest file.
+const ProjectFileName = "jet.project"
+
+// Project is a description of a multi-module Jet project
+// declared in the [ProjectFileName] file.
+//
+// The manifest consists of `key = value` lines, where the value
+// is either a string or a list of strings. Lists may span
+// several lines. Comments start with '#'.
+//
+//	name = "tetris"
+//	main = "src/Main.jet"
+//	source_dirs = ["src", "vendor"]
+//	ldflags = ["-lraylib"]
+//
+// All paths are relative to the directory of the manifest.
+type Project struct {
+	// Directory containing the manifest.
+	Dir string
+
+	// Name of the package. Used as the name of the executable.
+	Name string
+
+	// Path to the entry module.
+	Main string
+
+	// Directories used to search for the imported modules.
+	SourceDirs []string
+
+	// Path to the core library.
+	LibPath string
+
+	// Directories passed to the C compiler via '-I'.
+	IncludeDirs []string
+
+	// Additional flags passed to the C compiler.
+	CFlags []string
+
+	// Additional flags passed to the C compiler when linking.
+	LDFlags []string
+
+	// Directory of the generated C code and the executable.
+	OutputDir string
+}
+
+// Searches for the project manifest in the specified directory and
+// its parents. Returns an empty string if the manifest was not found.
+func FindProject(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+
+	for {
+		path := filepath.Join(dir, ProjectFileName)
+
+		if stat, err := os.Stat(path); err == nil && stat.Mode().IsRegular() {
+			return path
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+
+		dir = parent
+	}
+}
+
+// Reads and parses the project manifest.
+func LoadProject(path string) (*Project, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseProject(path, content)
+}
+
+// Parses the project manifest located at the specified path.
+func ParseProject(path string, content []byte) (*Project, error) {
+	project := &Project{
+		Dir:        filepath.Dir(path),
+		SourceDirs: []string{"."},
+		OutputDir:  ".jet",
+	}
+	seen := map[string]bool{}
+	lines := strings.Split(string(content), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		lineNum := i + 1
+		line := trimProjectComment(lines[i])
+
+		if line == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, projectErrorf(path, lineNum, "expected 'key = value'")
+		}
+
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		if seen[key] {
+			return nil, projectErrorf(path, lineNum, "duplicate key '%s'", key)
+		}
+
+		seen[key] = true
+
+		// Multiline list.
+		if strings.HasPrefix(value, "[") {
+			for !strings.HasSuffix(value, "]") && i+1 < len(lines) {
+				i++
+				value += " " + trimProjectComment(lines[i])
+			}
+		}
+
+		switch key {
+		case "name", "main", "lib_path", "output_dir":
+			str, err := parseProjectString(value)
+			if err != nil {
+				return nil, projectErrorf(path, lineNum, "%s", err.Error())
+			}
+
+			switch key {
+			case "name":
+				project.Name = str
+
+			case "main":
+				project.Main = project.path(str)
+
+			case "lib_path":
+				project.LibPath = project.path(str)
+
+			case "output_dir":
+				project.OutputDir = str
+			}
+
+		case "source_dirs", "include_dirs", "cflags", "ldflags":
+			list, err := parseProjectList(value)
+			if err != nil {
+				return nil, projectErrorf(path, lineNum, "%s", err.Error())
+			}
+
+			switch key {
+			case "source_dirs":
+				project.SourceDirs = list
+
+			case "include_dirs":
+				project.IncludeDirs = list
+
+			case "cflags":
+				project.CFlags = list
+
+			case "ldflags":
+				project.LDFlags = list
+			}
+
+		default:
+			return nil, projectErrorf(path, lineNum, "unknown key '%s'", key)
+		}
+	}
+
+	if project.Main == "" {
+		return nil, fmt.Errorf("%s: the entry module is not specified (key 'main')", path)
+	}
+
+	if project.Name == "" {
+		name := filepath.Base(project.Main)
+		project.Name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+
+	for i, dir := range project.SourceDirs {
+		project.SourceDirs[i] = project.path(dir)
+	}
+
+	for i, dir := range project.IncludeDirs {
+		project.IncludeDirs[i] = project.path(dir)
+	}
+
+	project.OutputDir = project.path(project.OutputDir)
+	return project, nil
+}
+
+// Returns the flags passed to the C compiler when compiling.
+func (project *Project) CompilerFlags() []string {
+	flags := make([]string, 0, len(project.IncludeDirs)+len(project.CFlags))
+
+	for _, dir := range project.IncludeDirs {
+		flags = append(flags, "-I"+dir)
+	}
+
+	return append(flags, project.CFlags...)
+}
+
+// Returns the path relative to the project directory.
+func (project *Project) path(path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+
+	return filepath.Join(project.Dir, path)
+}
+
+func trimProjectComment(line string) string {
+	inString := false
+
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			if inString {
+				i++
+			}
+
+		case '"':
+			inString = !inString
+
+		case '#':
+			if !inString {
+				return strings.TrimSpace(line[:i])
+			}
+		}
+	}
+
+	return strings.TrimSpace(line)
+}
+
+func parseProjectString(value string) (string, error) {
+	if !strings.HasPrefix(value, `"`) {
+		return "", fmt.Errorf("expected string, found '%s'", value)
+	}
+
+	str, err := strconv.Unquote(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid string %s", value)
+	}
+
+	return str, nil
+}
+
+func parseProjectList(value string) ([]string, error) {
+	if !strings.HasPrefix(value, "[") || !strings.HasSuffix(value, "]") {
+		return nil, fmt.Errorf("expected list of strings, found '%s'", value)
+	}
+
+	value = strings.TrimSpace(value[1 : len(value)-1])
+	list := []string{}
+
+	for value != "" {
+		if !strings.HasPrefix(value, `"`) {
+			return nil, fmt.Errorf("expected string, found '%s'", value)
+		}
+
+		prefix, err := strconv.QuotedPrefix(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid string %s", value)
+		}
+
+		str, _ := strconv.Unquote(prefix)
+		list = append(list, str)
+		value = strings.TrimSpace(value[len(prefix):])
+
+		if value != "" {
+			if value[0] != ',' {
+				return nil, fmt.Errorf("expected ',' after %s", prefix)
+			}
+
+			value = strings.TrimSpace(value[1:])
+		}
+	}
+
+	return list, nil
+}
+
+func projectErrorf(path string, line int, format string, args ...any) error {
+	return fmt.Errorf("%s:%d: %s", path, line, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestParseProject(t *testing.T) {
+	const content = `# comment
+name = "demo" # trailing comment
+main = "src/Main.jet"
+source_dirs = [
+	"src",
+	"vendor",
+]
+include_dirs = ["include"]
+ldflags = ["-lraylib", "-lm"]
+`
+	dir := filepath.FromSlash("/project")
+	project, err := ParseProject(filepath.Join(dir, ProjectFileName), []byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if project.Name != "demo" {
+		t.Errorf("unexpected name; want %q, have %q", "demo", project.Name)
+	}
+
+	if want := filepath.Join(dir, "src", "Main.jet"); project.Main != want {
+		t.Errorf("unexpected main; want %q, have %q", want, project.Main)
+	}
+
+	wantDirs := []string{filepath.Join(dir, "src"), filepath.Join(dir, "vendor")}
+	if !slices.Equal(project.SourceDirs, wantDirs) {
+		t.Errorf("unexpected source dirs; want %v, have %v", wantDirs, project.SourceDirs)
+	}
+
+	wantFlags := []string{"-I" + filepath.Join(dir, "include")}
+	if !slices.Equal(project.CompilerFlags(), wantFlags) {
+		t.Errorf("unexpected compiler flags; want %v, have %v", wantFlags, project.CompilerFlags())
+	}
+
+	if !slices.Equal(project.LDFlags, []string{"-lraylib", "-lm"}) {
+		t.Errorf("unexpected linker flags: %v", project.LDFlags)
+	}
+
+	if want := filepath.Join(dir, ".jet"); project.OutputDir != want {
+		t.Errorf("unexpected output dir; want %q, have %q", want, project.OutputDir)
+	}
+}
+
+func TestParseProjectFail(t *testing.T) {
+	tests := map[string]string{
+		`name = "demo"`:                            "jet.project: the entry module is not specified (key 'main')",
+		"main = \"a.jet\"\nfoo = \"bar\"":          "jet.project:2: unknown key 'foo'",
+		"main = a.jet":                             "jet.project:1: expected string, found 'a.jet'",
+		`main = "a.jet"` + "\nmain = \"b\"":        "jet.project:2: duplicate key 'main'",
+		"main = \"a.jet\"\ncflags = [\"a\" \"b\"]": `jet.project:2: expected ',' after "a"`,
+	}
+
+	for content, want := range tests {
+		_, err := ParseProject(ProjectFileName, []byte(content))
+		if err == nil {
+			t.Errorf("expected error %q", want)
+		} else if err.Error() != want {
+			t.Errorf("unexpected error; want %q, have %q", want, err.Error())
+		}
+	}
+}
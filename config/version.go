@@ -0,0 +1,36 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Version of the compiler.
+const Version = "0.1.0"
+
+// Returns the path to the core library specified by the '--lib_path'
+// flag, the project manifest or the 'lib' directory next to the
+// compiler executable.
+func CoreLibPath(project *Project) string {
+	if FlagCoreLibPath != "" {
+		return filepath.Clean(FlagCoreLibPath)
+	}
+
+	if project != nil && project.LibPath != "" {
+		return project.LibPath
+	}
+
+	return filepath.Join(filepath.Dir(Exe), "lib")
+}
+
+// Returns the version of the core library stored in the 'VERSION'
+// file of the core library directory.
+func CoreLibVersion(libPath string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(libPath, "VERSION"))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(content)), nil
+}
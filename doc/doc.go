@@ -0,0 +1,299 @@
+// Package doc extracts the documentation of a checked module from
+// its doc comments and renders it to Markdown or HTML.
+//
+// Doc comments start with '##' and are placed on the lines right
+// before the declaration, struct field or enum member:
+//
+//	## Returns the n-th Fibonacci number.
+//	func fib(n i32) i32 { ... }
+package doc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/saffage/jet/ast"
+	"github.com/saffage/jet/checker"
+	"github.com/saffage/jet/types"
+)
+
+// Kind of the documented declaration.
+type Kind byte
+
+const (
+	KindConst Kind = iota
+	KindVar
+	KindType
+	KindFunc
+)
+
+// Module contains the documentation of the module declarations.
+type Module struct {
+	Name string
+
+	// Declarations in the source order.
+	Decls []*Decl
+}
+
+// Decl is a documented declaration of the module scope.
+type Decl struct {
+	Kind Kind
+	Name string
+
+	// Declaration with the resolved types, e.g. 'func f(x i32) i32'.
+	Signature string
+
+	Doc        string
+	Attributes []string
+
+	// Struct fields or enum members. Enum members have no type.
+	Fields []*Field
+}
+
+type Field struct {
+	Name       string
+	Type       string
+	Doc        string
+	Attributes []string
+}
+
+// Returns the documentation of the declarations of the module.
+func New(m *checker.Module) *Module {
+	moduleDecl, _ := m.Node().(*ast.ModuleDecl)
+	if moduleDecl == nil {
+		panic("module has no declaration")
+	}
+
+	d := &Module{Name: m.Name()}
+	x := extractor{modules: importedModules(m)}
+
+	list, _ := moduleDecl.Body.(*ast.List)
+	if list == nil {
+		return d
+	}
+
+	for _, node := range list.Nodes {
+		decl, _ := node.(ast.Decl)
+		if decl == nil {
+			continue
+		}
+
+		sym, _ := m.Defs.Get(decl.Ident())
+		if sym == nil {
+			continue
+		}
+
+		if docDecl := x.decl(decl, sym); docDecl != nil {
+			d.Decls = append(d.Decls, docDecl)
+		}
+	}
+
+	return d
+}
+
+// Returns the declarations of the specified kind.
+func (m *Module) Filter(kind Kind) []*Decl {
+	decls := []*Decl{}
+
+	for _, decl := range m.Decls {
+		if decl.Kind == kind {
+			decls = append(decls, decl)
+		}
+	}
+
+	return decls
+}
+
+type extractor struct {
+	// Modules whose types can be referenced by name.
+	modules []*checker.Module
+}
+
+func (x *extractor) decl(node ast.Decl, sym checker.Symbol) *Decl {
+	d := &Decl{
+		Name:       sym.Name(),
+		Doc:        node.Doc(),
+		Attributes: attributes(node.Attributes()),
+	}
+
+	switch sym := sym.(type) {
+	case *checker.Const:
+		d.Kind = KindConst
+
+		if types.IsUntyped(sym.Type()) {
+			d.Signature = fmt.Sprintf("const %s = %s", sym.Name(), sym.Value())
+		} else {
+			d.Signature = fmt.Sprintf("const %s %s = %s", sym.Name(), x.typeString(sym.Type()), sym.Value())
+		}
+
+	case *checker.Var:
+		d.Kind = KindVar
+		keyword := "var"
+		if sym.IsVal() {
+			keyword = "val"
+		}
+
+		d.Signature = fmt.Sprintf("%s %s %s", keyword, sym.Name(), x.typeString(sym.Type()))
+
+	case *checker.Func:
+		d.Kind = KindFunc
+		d.Signature = x.funcSignature(sym)
+
+	case *checker.Struct:
+		d.Kind = KindType
+		d.Signature = "struct " + sym.Name()
+		d.Fields = x.structFields(sym)
+
+	case *checker.Enum:
+		d.Kind = KindType
+		d.Signature = "enum " + sym.Name()
+		d.Fields = enumMembers(sym.Node().(*ast.EnumDecl))
+
+	case *checker.TypeAlias:
+		d.Kind = KindType
+		alias := types.AsTypeDesc(sym.Type()).Base().(*types.Alias)
+		d.Signature = fmt.Sprintf("alias %s = %s", sym.Name(), x.typeString(alias.Base()))
+
+	default:
+		return nil
+	}
+
+	return d
+}
+
+func (x *extractor) funcSignature(sym *checker.Func) string {
+	buf := strings.Builder{}
+	buf.WriteString("func ")
+	buf.WriteString(sym.Name())
+	buf.WriteByte('(')
+
+	for i, param := range sym.Params() {
+		if i != 0 {
+			buf.WriteString(", ")
+		}
+
+		buf.WriteString(param.Name())
+
+		if i == len(sym.Params())-1 && sym.Variadic() {
+			buf.WriteString("...")
+		} else {
+			buf.WriteByte(' ')
+			buf.WriteString(x.typeString(param.Type()))
+		}
+	}
+
+	buf.WriteByte(')')
+
+	if result := sym.Type().(*types.Func).Result(); !result.Equals(types.Unit) {
+		buf.WriteByte(' ')
+
+		if result.Len() == 1 {
+			buf.WriteString(x.typeString(result.Types()[0]))
+		} else {
+			buf.WriteString(x.typeString(result))
+		}
+	}
+
+	return buf.String()
+}
+
+func (x *extractor) structFields(sym *checker.Struct) []*Field {
+	node := sym.Node().(*ast.StructDecl)
+	t := types.AsStruct(types.SkipTypeDesc(sym.Type()))
+	fields := make([]*Field, 0, len(t.Fields()))
+
+	for i, field := range t.Fields() {
+		binding := node.Body.Nodes[i].(*ast.Binding)
+		fields = append(fields, &Field{
+			Name:       field.Name,
+			Type:       x.typeString(field.Type),
+			Doc:        binding.Doc(),
+			Attributes: attributes(binding.Attrs),
+		})
+	}
+
+	return fields
+}
+
+func enumMembers(node *ast.EnumDecl) []*Field {
+	members := make([]*Field, 0, len(node.Body.Nodes))
+
+	for _, member := range node.Body.Nodes {
+		if ident, _ := member.(*ast.Ident); ident != nil {
+			members = append(members, &Field{Name: ident.Name, Doc: node.MemberDoc(ident)})
+		}
+	}
+
+	return members
+}
+
+// Returns the string representation of the type. Unlike [types.Type.String],
+// the types declared in the modules are referenced by name.
+func (x *extractor) typeString(t types.Type) string {
+	for _, m := range x.modules {
+		if sym := m.TypeSyms[t]; sym != nil {
+			return sym.Name()
+		}
+	}
+
+	switch t := t.(type) {
+	case *types.Ref:
+		return "*" + x.typeString(t.Base())
+
+	case *types.Array:
+		if t.Size() == -1 {
+			return "[_]" + x.typeString(t.ElemType())
+		}
+
+		return fmt.Sprintf("[%d]%s", t.Size(), x.typeString(t.ElemType()))
+
+	case *types.Tuple:
+		elems := make([]string, t.Len())
+
+		for i, elem := range t.Types() {
+			elems[i] = x.typeString(elem)
+		}
+
+		return "(" + strings.Join(elems, ", ") + ")"
+
+	default:
+		return t.String()
+	}
+}
+
+func attributes(attrs *ast.AttributeList) []string {
+	if attrs == nil {
+		return nil
+	}
+
+	result := make([]string, 0, len(attrs.List.Exprs))
+
+	for _, expr := range attrs.List.Exprs {
+		result = append(result, expr.String())
+	}
+
+	return result
+}
+
+// Returns the module and all of the modules imported by it.
+func importedModules(m *checker.Module) []*checker.Module {
+	modules := []*checker.Module{}
+	visited := map[*checker.Module]bool{}
+
+	var visit func(m *checker.Module)
+	visit = func(m *checker.Module) {
+		if visited[m] {
+			return
+		}
+
+		visited[m] = true
+		modules = append(modules, m)
+
+		for _, imported := range m.Imports {
+			visit(imported)
+		}
+	}
+
+	visit(m)
+	return modules
+}
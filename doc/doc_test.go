@@ -0,0 +1,144 @@
+package doc
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/saffage/jet/checker"
+	"github.com/saffage/jet/config"
+)
+
+const source = `## Maximum number of the shapes.
+const MaxShapes = 16
+
+## Color of a shape.
+##
+## Channels are stored in RGBA order.
+struct Color {
+    ## Red channel.
+    r u8
+    a u8
+}
+
+## Kind of the shape.
+enum Kind {
+    ## A circle.
+    Circle
+    Square
+}
+
+# Not a doc comment.
+var counter = 0
+
+## Allocates memory.
+@(ExternC) func malloc(size u64) pointer
+
+## Paints the shape.
+
+func paint(color *Color) Color {
+    ## Not a declaration doc.
+    *color
+}
+`
+
+func checkModule(t *testing.T, name, source string) *checker.Module {
+	t.Helper()
+
+	cfg := config.New()
+	cfg.LibPath = filepath.Join("..", "lib")
+	cfg.Files[config.MainFileID] = config.FileInfo{
+		Name: name,
+		Path: name + ".jet",
+		Buf:  bytes.NewBufferString(source),
+	}
+
+	env, errs := checker.NewEnv(cfg)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	m, errs := env.CheckFile(config.MainFileID)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	return m
+}
+
+func TestNew(t *testing.T) {
+	m := New(checkModule(t, "Shapes", source))
+
+	expected := []struct {
+		name, signature, doc string
+		attributes           []string
+	}{
+		{"MaxShapes", "const MaxShapes = 16", "Maximum number of the shapes.", nil},
+		{"Color", "struct Color", "Color of a shape.\n\nChannels are stored in RGBA order.", nil},
+		{"Kind", "enum Kind", "Kind of the shape.", nil},
+		{"counter", "var counter i32", "", nil},
+		{"malloc", "func malloc(size u64) pointer", "Allocates memory.", []string{"ExternC"}},
+		{"paint", "func paint(color *Color) Color", "", nil},
+	}
+
+	if len(m.Decls) != len(expected) {
+		t.Fatalf("expected %d declarations, got %d", len(expected), len(m.Decls))
+	}
+
+	for i, decl := range m.Decls {
+		e := expected[i]
+
+		if decl.Name != e.name || decl.Signature != e.signature || decl.Doc != e.doc {
+			t.Errorf("unexpected declaration %q: %q, %q", decl.Name, decl.Signature, decl.Doc)
+		}
+
+		if strings.Join(decl.Attributes, ",") != strings.Join(e.attributes, ",") {
+			t.Errorf("unexpected attributes of %q: %v", decl.Name, decl.Attributes)
+		}
+	}
+
+	if fields := m.Decls[1].Fields; len(fields) != 2 || fields[0].Doc != "Red channel." || fields[0].Type != "u8" || fields[1].Doc != "" {
+		t.Errorf("unexpected struct fields: %+v", fields)
+	}
+
+	if members := m.Decls[2].Fields; len(members) != 2 || members[0].Doc != "A circle." || members[1].Doc != "" {
+		t.Errorf("unexpected enum members: %+v", members)
+	}
+}
+
+func TestRender(t *testing.T) {
+	m := New(checkModule(t, "Shapes", source))
+
+	markdown := strings.Builder{}
+	if err := m.Markdown(&markdown); err != nil {
+		t.Fatal(err)
+	}
+
+	html := strings.Builder{}
+	if err := m.HTML(&html); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{
+		"# Module `Shapes`",
+		"## Types",
+		"```jet\n@(ExternC)\nfunc malloc(size u64) pointer\n```",
+		"struct Color {\n    r u8\n    a u8\n}",
+		"- `r`: Red channel.",
+	} {
+		if !strings.Contains(markdown.String(), s) {
+			t.Errorf("expected %q in Markdown:\n%s", s, markdown.String())
+		}
+	}
+
+	for _, s := range []string{
+		"<title>Module Shapes</title>",
+		`<h3 id="paint">paint</h3>`,
+		"<pre>func paint(color *Color) Color</pre>",
+	} {
+		if !strings.Contains(html.String(), s) {
+			t.Errorf("expected %q in HTML:\n%s", s, html.String())
+		}
+	}
+}
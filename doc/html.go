@@ -0,0 +1,76 @@
+package doc
+
+import (
+	"html/template"
+	"io"
+)
+
+type htmlSection struct {
+	Title string
+	Decls []*Decl
+}
+
+// Writes the documentation as a static HTML page.
+func (m *Module) HTML(w io.Writer) error {
+	htmlSections := make([]htmlSection, 0, len(sections))
+
+	for _, section := range sections {
+		if decls := m.Filter(section.kind); len(decls) > 0 {
+			htmlSections = append(htmlSections, htmlSection{section.title, decls})
+		}
+	}
+
+	return htmlTemplate.Execute(w, struct {
+		Name     string
+		Sections []htmlSection
+	}{m.Name, htmlSections})
+}
+
+var htmlTemplate = template.Must(template.New("module").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Module {{.Name}}</title>
+<style>
+body { max-width: 860px; margin: 0 auto; padding: 16px; font-family: sans-serif; line-height: 1.5; }
+pre { background: #f4f4f5; padding: 8px 12px; border-radius: 4px; overflow-x: auto; }
+code, pre { font-family: monospace; }
+.doc { white-space: pre-wrap; }
+nav ul { list-style: none; padding-left: 16px; }
+</style>
+</head>
+<body>
+<h1>Module <code>{{.Name}}</code></h1>
+<nav>
+{{- range .Sections}}
+<h4>{{.Title}}</h4>
+<ul>
+{{- range .Decls}}
+<li><a href="#{{.Name}}">{{.Name}}</a></li>
+{{- end}}
+</ul>
+{{- end}}
+</nav>
+{{- range .Sections}}
+<h2>{{.Title}}</h2>
+{{- range .Decls}}
+<h3 id="{{.Name}}">{{.Name}}</h3>
+<pre>{{.Code}}</pre>
+{{- if .Doc}}
+<p class="doc">{{.Doc}}</p>
+{{- end}}
+{{- if .HasFieldDocs}}
+<dl>
+{{- range .Fields}}
+{{- if .Doc}}
+<dt><code>{{.Name}}</code></dt>
+<dd class="doc">{{.Doc}}</dd>
+{{- end}}
+{{- end}}
+</dl>
+{{- end}}
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
@@ -0,0 +1,118 @@
+package doc
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Section of the rendered documentation.
+type section struct {
+	title string
+	kind  Kind
+}
+
+var sections = []section{
+	{"Constants", KindConst},
+	{"Variables", KindVar},
+	{"Types", KindType},
+	{"Functions", KindFunc},
+}
+
+// Writes the documentation in Markdown format.
+func (m *Module) Markdown(w io.Writer) error {
+	buf := strings.Builder{}
+	fmt.Fprintf(&buf, "# Module `%s`\n", m.Name)
+
+	for _, section := range sections {
+		decls := m.Filter(section.kind)
+		if len(decls) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&buf, "\n## %s\n", section.title)
+
+		for _, decl := range decls {
+			fmt.Fprintf(&buf, "\n### %s\n\n```jet\n%s\n```\n", decl.Name, decl.Code())
+
+			if decl.Doc != "" {
+				fmt.Fprintf(&buf, "\n%s\n", decl.Doc)
+			}
+
+			if decl.HasFieldDocs() {
+				buf.WriteByte('\n')
+
+				for _, field := range decl.Fields {
+					if field.Doc != "" {
+						fmt.Fprintf(&buf, "- `%s`: %s\n", field.Name, indentLines(field.Doc, "  "))
+					}
+				}
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+// Returns the declaration as it is displayed in the code block:
+// the attributes, the signature and the fields.
+func (d *Decl) Code() string {
+	buf := strings.Builder{}
+
+	if len(d.Attributes) > 0 {
+		fmt.Fprintf(&buf, "@(%s)\n", strings.Join(d.Attributes, ", "))
+	}
+
+	buf.WriteString(d.Signature)
+
+	if d.Fields == nil {
+		return buf.String()
+	}
+
+	if len(d.Fields) == 0 {
+		buf.WriteString(" {}")
+		return buf.String()
+	}
+
+	buf.WriteString(" {\n")
+	width := 0
+
+	for _, field := range d.Fields {
+		width = max(width, len(field.Name))
+	}
+
+	for _, field := range d.Fields {
+		buf.WriteString("    ")
+
+		if len(field.Attributes) > 0 {
+			fmt.Fprintf(&buf, "@(%s) ", strings.Join(field.Attributes, ", "))
+		}
+
+		if field.Type == "" {
+			buf.WriteString(field.Name)
+		} else {
+			fmt.Fprintf(&buf, "%-*s %s", width, field.Name, field.Type)
+		}
+
+		buf.WriteByte('\n')
+	}
+
+	buf.WriteByte('}')
+	return buf.String()
+}
+
+// Reports whether any of the fields has a doc comment.
+func (d *Decl) HasFieldDocs() bool {
+	for _, field := range d.Fields {
+		if field.Doc != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func indentLines(s, indent string) string {
+	return strings.ReplaceAll(s, "\n", "\n"+indent)
+}
@@ -0,0 +1,49 @@
+// Package format implements the canonical formatting of Jet source code.
+//
+// The code is laid out as follows:
+//   - statements are indented with 4 spaces, one statement per line;
+//   - at most one blank line is kept between statements;
+//   - struct fields, declarations with a value, assignments and fields of
+//     struct literals on adjacent lines are aligned by the end of their
+//     operator, e.g. `x     = 1` and `count += 1`;
+//   - lists keep their layout: a list whose first element starts on a new
+//     line is printed one element per line, otherwise on a single line;
+//   - binary operators are surrounded by spaces and only the required
+//     parentheses are kept;
+//   - comments are kept at their positions relative to the statements.
+package format
+
+import (
+	"github.com/saffage/jet/config"
+	"github.com/saffage/jet/parser"
+	"github.com/saffage/jet/scanner"
+)
+
+// Formats the source code of a module. The code is not formatted if it
+// contains syntax errors, the errors are returned instead. Locations
+// of the errors refer to the specified file.
+func Source(fileID config.FileID, src []byte) ([]byte, []error) {
+	tokens, errs := scanner.Scan(src, fileID, scanner.SkipWhitespace)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	list, comments, errs := parser.ParseWithComments(config.New(), tokens, parser.DefaultFlags)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	p := newPrinter(src, comments)
+
+	if list != nil {
+		p.stmtList(list.Nodes, uint64(len(src)))
+	} else {
+		p.flushComments(uint64(len(src)))
+	}
+
+	if p.buf.Len() > 0 {
+		p.newline()
+	}
+
+	return p.buf.Bytes(), nil
+}
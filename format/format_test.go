@@ -0,0 +1,180 @@
+package format
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/saffage/jet/config"
+)
+
+func TestSource(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "operators",
+			input:    "func f(n int) int {\n  fib(n-1)+fib((n-2))*3\n}\n",
+			expected: "func f(n int) int {\n    fib(n - 1) + fib(n - 2) * 3\n}\n",
+		},
+		{
+			name:     "required parentheses",
+			input:    "func f() { (a+b)*c; a-(b-c) }",
+			expected: "func f() {\n    (a + b) * c\n    a - (b - c)\n}\n",
+		},
+		{
+			name:     "aligned constants",
+			input:    "const A = 1\nconst Long = 2\n\nconst B = 3\n",
+			expected: "const A    = 1\nconst Long = 2\n\nconst B = 3\n",
+		},
+		{
+			name:     "aligned struct fields",
+			input:    "struct Rect {\n  x f32\n  width f32\n}\n",
+			expected: "struct Rect {\n    x     f32\n    width f32\n}\n",
+		},
+		{
+			name: "aligned variables and assignments",
+			input: "var CyanColor   = Color.{ r = 0x06; g = 0xb6 }\n" +
+				"var RedColor    = Color.{ r = 0xdc; g = 0x26 }\n" +
+				"var PurpleColor = Color.{ r = 0x93; g = 0x33 }\n" +
+				"\n" +
+				"func f() {\n" +
+				"    tetramino?.rotation  = 0\n" +
+				"    tetramino?.x         = 3\n" +
+				"    tetramino?.tetramino = next()\n" +
+				"    shuffler?.index     += 1\n" +
+				"}\n",
+			expected: "var CyanColor   = Color.{ r = 0x06; g = 0xb6 }\n" +
+				"var RedColor    = Color.{ r = 0xdc; g = 0x26 }\n" +
+				"var PurpleColor = Color.{ r = 0x93; g = 0x33 }\n" +
+				"\n" +
+				"func f() {\n" +
+				"    tetramino?.rotation  = 0\n" +
+				"    tetramino?.x         = 3\n" +
+				"    tetramino?.tetramino = next()\n" +
+				"    shuffler?.index     += 1\n" +
+				"}\n",
+		},
+		{
+			name:     "declaration without a value",
+			input:    "var a = 1\nval long i32 = 2\nvar b i32\nx += 1\n",
+			expected: "var a        = 1\nval long i32 = 2\nvar b i32\nx += 1\n",
+		},
+		{
+			name: "aligned struct literal fields",
+			input: "func f() {\n" +
+				"    var cellX = 1\n" +
+				"    var cell = Rectangle.{\n" +
+				"        x = cellX\n" +
+				"        width = 2\n" +
+				"        # Comment.\n" +
+				"        height = 3\n" +
+				"    }\n" +
+				"}\n",
+			expected: "func f() {\n" +
+				"    var cellX = 1\n" +
+				"    var cell  = Rectangle.{\n" +
+				"        x     = cellX\n" +
+				"        width = 2\n" +
+				"        # Comment.\n" +
+				"        height = 3\n" +
+				"    }\n" +
+				"}\n",
+		},
+		{
+			name:     "comments",
+			input:    "# Module comment.\n\n\n\nfunc main() {   # Trailing.\n    # Inner.\n    foo()\n}\n# Last.",
+			expected: "# Module comment.\n\nfunc main() { # Trailing.\n    # Inner.\n    foo()\n}\n# Last.\n",
+		},
+		{
+			name:     "literals",
+			input:    "var s = 'a\\tb'\nvar x = 0xff\n",
+			expected: "var s = 'a\\tb'\nvar x = 0xff\n",
+		},
+		{
+			name:     "empty statement",
+			input:    "func f() {\n    foo();;\n}\n",
+			expected: "func f() {\n    foo()\n    ;\n}\n",
+		},
+		{
+			name:     "labelled loop",
+			input:    "func f() {\n  outer:while a { while b { break  outer } }\n}\n",
+			expected: "func f() {\n    outer: while a { while b { break outer } }\n}\n",
+		},
+		{
+			name:     "for loop",
+			input:    "func f() {\n  for i,x in arr {}\n  for i in 0 ..< n-1 {}\n}\n",
+			expected: "func f() {\n    for i, x in arr {}\n    for i in 0..<n - 1 {}\n}\n",
+		},
+		{
+			name:     "val declaration",
+			input:    "val  x i32=1\nfunc f() {\n  val y = x\n}\n",
+			expected: "val x i32 = 1\nfunc f() {\n    val y = x\n}\n",
+		},
+		{
+			name:     "match",
+			input:    "func f() {\n  match c { Color.Red,Color.Green => x=1; _ => return }\n}\n",
+			expected: "func f() {\n    match c {\n        Color.Red, Color.Green => x = 1\n        _ => return\n    }\n}\n",
+		},
+		{
+			name:     "empty file",
+			input:    "\n\n",
+			expected: "",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			output := formatString(t, c.input)
+
+			if output != c.expected {
+				t.Fatalf("unexpected output:\n%s\nexpected:\n%s", output, c.expected)
+			}
+
+			if again := formatString(t, output); again != output {
+				t.Fatalf("formatting is not idempotent:\n%s\nexpected:\n%s", again, output)
+			}
+		})
+	}
+}
+
+func TestSourceExamples(t *testing.T) {
+	paths, err := filepath.Glob("../examples/*.jet")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			src, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			output := formatString(t, string(src))
+
+			if again := formatString(t, output); again != output {
+				t.Fatalf("formatting is not idempotent:\n%s\nexpected:\n%s", again, output)
+			}
+		})
+	}
+}
+
+func TestSourceErrors(t *testing.T) {
+	if _, errs := Source(config.MainFileID, []byte("func f( {}")); len(errs) == 0 {
+		t.Fatal("expected syntax errors")
+	}
+}
+
+func formatString(t *testing.T, src string) string {
+	t.Helper()
+
+	output, errs := Source(config.MainFileID, []byte(src))
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	return string(output)
+}
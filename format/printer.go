@@ -0,0 +1,791 @@
+package format
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/saffage/jet/ast"
+	"github.com/saffage/jet/token"
+)
+
+const indentString = "    "
+
+type printer struct {
+	src    []byte
+	buf    bytes.Buffer
+	indent int
+
+	// Nothing is written on the current line yet.
+	lineStart bool
+
+	// Comments that are not printed yet, in the source order.
+	comments []*ast.Comment
+
+	// Source line of the last printed statement, list element or comment.
+	// Used to keep blank lines and to place trailing comments.
+	lastLine uint32
+
+	// Blank lines are not kept before the first statement of a block.
+	blockStart bool
+}
+
+func newPrinter(src []byte, groups []*ast.CommentGroup) *printer {
+	p := &printer{src: src, lineStart: true}
+
+	for _, group := range groups {
+		p.comments = append(p.comments, group.Comments...)
+	}
+
+	return p
+}
+
+func (p *printer) write(s string) {
+	if s == "" {
+		return
+	}
+
+	if p.lineStart {
+		p.buf.WriteString(strings.Repeat(indentString, p.indent))
+		p.lineStart = false
+	}
+
+	p.buf.WriteString(s)
+}
+
+func (p *printer) newline() {
+	p.buf.WriteByte('\n')
+	p.lineStart = true
+}
+
+// Starts a new line for the code located at the specified source line.
+// A blank line is kept if the code was separated by blank lines.
+func (p *printer) linebreak(line uint32) {
+	blank := line > p.lastLine+1 && !p.blockStart
+	p.blockStart = false
+
+	if p.buf.Len() == 0 {
+		return
+	}
+
+	if !p.lineStart {
+		p.newline()
+	}
+
+	if blank {
+		p.newline()
+	}
+}
+
+// Prints the comments located before the offset. A comment located on
+// the line of the last printed code is printed at the end of that line.
+func (p *printer) flushComments(before uint64) {
+	for len(p.comments) > 0 && p.comments[0].Start.Offset < before {
+		comment := p.comments[0]
+		p.comments = p.comments[1:]
+
+		if !p.lineStart && comment.Start.Line == p.lastLine {
+			p.write(" ")
+		} else {
+			p.linebreak(comment.Start.Line)
+		}
+
+		p.write("#" + strings.TrimRight(comment.Data, " \t\r"))
+		p.lastLine = comment.End.Line
+	}
+}
+
+// Reports whether there are comments between the offsets.
+func (p *printer) hasComments(from, to uint64) bool {
+	for _, comment := range p.comments {
+		if comment.Start.Offset >= to {
+			break
+		}
+
+		if comment.Start.Offset > from {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns the text printed by the function. Comments are not printed.
+func (p *printer) sprint(f func(p *printer)) string {
+	sub := &printer{src: p.src, lineStart: true}
+	f(sub)
+	return sub.buf.String()
+}
+
+//------------------------------------------------
+// Statements
+//------------------------------------------------
+
+// Prints the statements one per line. The comments located before
+// the end offset are printed after the statements.
+func (p *printer) stmtList(nodes []ast.Node, end uint64) {
+	widths := p.columns(nodes, p.alignedWidth)
+
+	for i, node := range nodes {
+		start := startOf(node)
+		p.flushComments(start.Offset)
+		p.linebreak(start.Line)
+		p.alignedNode(node, widths[i])
+		p.lastLine = node.LocEnd().Line
+	}
+
+	p.flushComments(end)
+}
+
+// Computes the widths of the aligned columns. The function returns the
+// width of the text before the column. Nodes starting on adjacent lines
+// are aligned together, blank lines and comments break the alignment.
+// The first line of a multiline node is aligned with the previous nodes.
+func (p *printer) columns(nodes []ast.Node, head func(ast.Node) (int, bool)) []int {
+	widths := make([]int, len(nodes))
+
+	for i := 0; i < len(nodes); {
+		width, ok := head(nodes[i])
+		if !ok {
+			i++
+			continue
+		}
+
+		j := i + 1
+
+		for ; j < len(nodes); j++ {
+			prev, next := nodes[j-1], nodes[j]
+			w, ok := head(next)
+
+			if !ok ||
+				!isSingleLine(prev) ||
+				startOf(next).Line != prev.LocEnd().Line+1 ||
+				p.hasComments(lineEnd(p.src, prev.LocEnd().Offset), startOf(next).Offset) {
+				break
+			}
+
+			width = max(width, w)
+		}
+
+		for ; i < j; i++ {
+			widths[i] = width
+		}
+	}
+
+	return widths
+}
+
+// Prints the block of statements.
+func (p *printer) block(node *ast.CurlyList) {
+	nodes := node.Nodes
+
+	if !p.hasComments(node.Open.Offset, node.Close.Offset) {
+		if len(nodes) == 0 {
+			p.write("{}")
+			return
+		}
+
+		if len(nodes) == 1 && node.Open.Line == node.Close.Line {
+			p.write("{ ")
+			p.node(nodes[0])
+			p.write(" }")
+			return
+		}
+	}
+
+	p.write("{")
+	p.indent++
+	p.lastLine = node.Open.Line
+	p.blockStart = true
+	p.stmtList(nodes, node.Close.Offset)
+	p.indent--
+	p.blockStart = false
+	p.newline()
+	p.write("}")
+}
+
+//------------------------------------------------
+// Lists
+//------------------------------------------------
+
+// Prints the list of expressions separated by commas. If the first
+// element starts on a new line, each element is printed on its own
+// line with a trailing comma.
+func (p *printer) exprList(open, close string, exprs []ast.Node, openLoc, closeLoc token.Loc) {
+	if !p.isMultiline(exprs, openLoc, closeLoc) {
+		p.write(open)
+
+		for i, expr := range exprs {
+			if i > 0 {
+				p.write(", ")
+			}
+
+			p.node(expr)
+		}
+
+		p.write(close)
+		return
+	}
+
+	p.lines(open, close, exprs, openLoc, closeLoc, func(_ int, expr ast.Node) {
+		p.node(expr)
+		p.write(",")
+	})
+}
+
+// Prints the list of elements separated by semicolons, e.g. the
+// fields of the struct literal. If the first element starts on a new
+// line, each element is printed on its own line.
+func (p *printer) curlyList(node *ast.CurlyList, elem func(i int, node ast.Node)) {
+	switch {
+	case len(node.Nodes) == 0 && !p.hasComments(node.Open.Offset, node.Close.Offset):
+		p.write("{}")
+
+	case !p.isMultiline(node.Nodes, node.Open, node.Close):
+		p.write("{ ")
+
+		for i, n := range node.Nodes {
+			if i > 0 {
+				p.write("; ")
+			}
+
+			elem(i, n)
+		}
+
+		p.write(" }")
+
+	default:
+		p.lines("{", "}", node.Nodes, node.Open, node.Close, elem)
+	}
+}
+
+func (p *printer) lines(open, close string, nodes []ast.Node, openLoc, closeLoc token.Loc, elem func(i int, node ast.Node)) {
+	p.write(open)
+	p.indent++
+	p.lastLine = openLoc.Line
+	p.blockStart = true
+
+	for i, node := range nodes {
+		start := startOf(node)
+		p.flushComments(start.Offset)
+		p.linebreak(start.Line)
+		elem(i, node)
+		p.lastLine = node.LocEnd().Line
+	}
+
+	p.flushComments(closeLoc.Offset)
+	p.indent--
+	p.blockStart = false
+	p.newline()
+	p.write(close)
+}
+
+func (p *printer) isMultiline(nodes []ast.Node, open, close token.Loc) bool {
+	return len(nodes) > 0 && startOf(nodes[0]).Line > open.Line ||
+		p.hasComments(open.Offset, close.Offset)
+}
+
+//------------------------------------------------
+// Nodes
+//------------------------------------------------
+
+func (p *printer) node(node ast.Node) {
+	switch node := node.(type) {
+	case *ast.BadNode:
+		p.write(node.String())
+
+	case *ast.Empty:
+		// The empty statement is significant, e.g. it discards
+		// the value of the block.
+		p.write(";")
+
+	case *ast.Ident:
+		p.write(node.Name)
+
+	case *ast.Literal:
+		// The value of the string literal is unescaped, so the
+		// literal is printed as it is written in the source.
+		p.write(string(p.src[node.Start.Offset : node.End.Offset+1]))
+
+	case *ast.Operator:
+		p.write(node.Kind.String())
+
+	case *ast.Binding:
+		p.binding(node, 0)
+
+	case *ast.BindingWithValue:
+		p.binding(node.Binding, 0)
+
+		if node.Value != nil {
+			p.write(" " + node.Operator.Kind.String() + " ")
+			p.node(node.Value)
+		}
+
+	case *ast.AttributeList:
+		p.write("@")
+		p.exprList("(", ")", node.List.Exprs, node.List.Open, node.List.Close)
+
+	case *ast.BuiltInCall:
+		p.write("@" + node.Name.Name)
+
+		switch args := node.Args.(type) {
+		case *ast.ParenList:
+			p.exprList("(", ")", args.Exprs, args.Open, args.Close)
+
+		case *ast.CurlyList:
+			p.write(" ")
+			p.block(args)
+		}
+
+	case *ast.Call:
+		p.operand(node.X)
+		p.exprList("(", ")", node.Args.Exprs, node.Args.Open, node.Args.Close)
+
+	case *ast.Index:
+		p.operand(node.X)
+		p.exprList("[", "]", node.Args.Exprs, node.Args.Open, node.Args.Close)
+
+	case *ast.ArrayType:
+		p.exprList("[", "]", node.Args.Exprs, node.Args.Open, node.Args.Close)
+		p.node(node.X)
+
+	case *ast.Signature:
+		if node.Loc.Line > 0 {
+			p.write("func")
+		}
+
+		p.exprList("(", ")", node.Params.Exprs, node.Params.Open, node.Params.Close)
+
+		if node.Result != nil {
+			p.write(" ")
+			p.node(node.Result)
+		}
+
+	case *ast.MemberAccess:
+		p.operand(node.X)
+		p.write(".")
+
+		if fields, ok := node.Selector.(*ast.CurlyList); ok {
+			widths := p.columns(fields.Nodes, p.alignedWidth)
+			p.curlyList(fields, func(i int, field ast.Node) { p.alignedNode(field, widths[i]) })
+		} else {
+			p.node(node.Selector)
+		}
+
+	case *ast.SafeMemberAccess:
+		p.operand(node.X)
+		p.write("?.")
+		p.node(node.Selector)
+
+	case *ast.PrefixOp:
+		p.write(node.Opr.Kind.String())
+
+		if _, isInfix := node.X.(*ast.InfixOp); isInfix {
+			p.write("(")
+			p.node(node.X)
+			p.write(")")
+		} else {
+			p.node(node.X)
+		}
+
+	case *ast.InfixOp:
+		p.infixOp(node)
+
+	case *ast.PostfixOp:
+		p.operand(node.X)
+		p.write(node.Opr.Kind.String())
+
+	case *ast.ParenList:
+		if len(node.Exprs) == 1 {
+			// Tuple with a single element.
+			p.write("(")
+			p.node(node.Exprs[0])
+			p.write(",)")
+		} else {
+			p.exprList("(", ")", node.Exprs, node.Open, node.Close)
+		}
+
+	case *ast.BracketList:
+		p.exprList("[", "]", node.Exprs, node.Open, node.Close)
+
+	case *ast.CurlyList:
+		p.block(node)
+
+	case *ast.If:
+		p.write("if ")
+		p.node(node.Cond)
+		p.write(" ")
+		p.block(node.Body)
+
+		if node.Else != nil {
+			p.write(" ")
+			p.node(node.Else)
+		}
+
+	case *ast.Else:
+		p.write("else ")
+		p.node(node.Body)
+
+	case *ast.Match:
+		p.write("match ")
+		p.node(node.X)
+		p.write(" ")
+		p.block(node.Body)
+
+	case *ast.MatchArm:
+		for i, pattern := range node.Patterns.Exprs {
+			if i != 0 {
+				p.write(", ")
+			}
+
+			p.node(pattern)
+		}
+
+		p.write(" => ")
+		p.node(node.X)
+
+	case *ast.While:
+		if node.Label != nil {
+			p.write(node.Label.Name + ": ")
+		}
+
+		p.write("while ")
+		p.node(node.Cond)
+		p.write(" ")
+		p.block(node.Body)
+
+	case *ast.For:
+		if node.Label != nil {
+			p.write(node.Label.Name + ": ")
+		}
+
+		p.write("for ")
+
+		if node.Index != nil {
+			p.write(node.Index.Name + ", ")
+		}
+
+		p.write(node.Value.Name + " in ")
+		p.node(node.X)
+		p.write(" ")
+		p.block(node.Body)
+
+	case *ast.Return:
+		p.write("return")
+
+		if node.X != nil {
+			p.write(" ")
+			p.node(node.X)
+		}
+
+	case *ast.Break:
+		p.write("break")
+
+		if node.Label != nil {
+			p.write(" " + node.Label.Name)
+		}
+
+	case *ast.Continue:
+		p.write("continue")
+
+		if node.Label != nil {
+			p.write(" " + node.Label.Name)
+		}
+
+	case *ast.Import:
+		p.write("import " + node.Module.Name)
+
+	case *ast.ModuleDecl:
+		p.attributes(node.Attrs)
+		p.write("module " + node.Name.Name)
+
+		if body, ok := node.Body.(*ast.CurlyList); ok {
+			p.write(" ")
+			p.block(body)
+		}
+
+	case *ast.VarDecl:
+		p.alignedNode(node, 0)
+
+	case *ast.ConstDecl:
+		p.alignedNode(node, 0)
+
+	case *ast.FuncDecl:
+		p.attributes(node.Attrs)
+		p.write("func " + node.Name.Name)
+		p.node(node.Signature)
+
+		if node.Body != nil {
+			p.write(" ")
+			p.block(node.Body)
+		}
+
+	case *ast.StructDecl:
+		p.attributes(node.Attrs)
+		p.write("struct " + node.Name.Name + " ")
+
+		widths := p.columns(node.Body.Nodes, func(node ast.Node) (int, bool) {
+			if field, ok := node.(*ast.Binding); ok && field.Type != nil {
+				return len(p.sprint(func(p *printer) { p.bindingName(field) })), true
+			}
+
+			return 0, false
+		})
+
+		p.curlyList(node.Body, func(i int, field ast.Node) {
+			if field, ok := field.(*ast.Binding); ok {
+				p.binding(field, widths[i])
+			} else {
+				p.node(field)
+			}
+		})
+
+	case *ast.EnumDecl:
+		p.attributes(node.Attrs)
+		p.write("enum " + node.Name.Name + " ")
+		p.curlyList(node.Body, func(_ int, member ast.Node) { p.node(member) })
+
+	case *ast.TypeAliasDecl:
+		p.attributes(node.Attrs)
+		p.write("alias " + node.Name.Name + " = ")
+		p.node(node.Expr)
+
+	default:
+		panic("unreachable")
+	}
+}
+
+// Prints the binding. The name is padded to the specified width.
+func (p *printer) binding(node *ast.Binding, width int) {
+	p.bindingName(node)
+
+	switch typ := node.Type.(type) {
+	case nil:
+
+	case *ast.Operator:
+		// Variadic parameter without type, e.g. `args...`.
+		p.node(typ)
+
+	default:
+		head := len(p.sprint(func(p *printer) { p.bindingName(node) }))
+		p.write(strings.Repeat(" ", max(0, width-head)+1))
+		p.node(typ)
+	}
+}
+
+func (p *printer) bindingName(node *ast.Binding) {
+	p.attributes(node.Attrs)
+	p.write(node.Name.Name)
+}
+
+// Prints the node. Declarations and assignments are aligned by the end
+// of their operator, the part before the operator is padded to the
+// specified width (see [printer.alignedWidth]).
+func (p *printer) alignedNode(node ast.Node, width int) {
+	head, op, value := p.split(node)
+
+	switch {
+	case head == nil:
+		p.node(node)
+
+	case op == "":
+		head(p)
+
+	default:
+		head(p)
+		p.write(strings.Repeat(" ", max(0, width-len(p.sprint(head))-len(op))))
+		p.write(" " + op + " ")
+		value(p)
+	}
+}
+
+// Returns the width of the node before the end of its operator, e.g.
+// `var x =` or `x +=` without the space before the operator. Reports
+// false if the node is not aligned.
+func (p *printer) alignedWidth(node ast.Node) (int, bool) {
+	head, op, _ := p.split(node)
+	if op == "" {
+		return 0, false
+	}
+
+	return len(p.sprint(head)) + len(op), true
+}
+
+// Splits the declaration or the assignment into the part before the
+// operator, the operator and the value. The operator is empty if the
+// declaration has no value, the head is nil for other nodes.
+func (p *printer) split(node ast.Node) (head func(p *printer), op string, value func(p *printer)) {
+	switch node := node.(type) {
+	case *ast.ConstDecl:
+		head = func(p *printer) {
+			p.attributes(node.Attrs)
+			p.write("const ")
+			p.binding(node.Binding.Binding, 0)
+		}
+
+		return head, node.Binding.Operator.Kind.String(), func(p *printer) { p.node(node.Binding.Value) }
+
+	case *ast.VarDecl:
+		head = func(p *printer) {
+			p.attributes(node.Attrs)
+
+			if node.IsVal {
+				p.write("val ")
+			} else {
+				p.write("var ")
+			}
+
+			p.binding(node.Binding, 0)
+		}
+
+		if node.Value == nil {
+			return head, "", nil
+		}
+
+		return head, "=", func(p *printer) { p.node(node.Value) }
+
+	case *ast.InfixOp:
+		// Assignments with the line break after the operator are
+		// printed by [printer.infixOp].
+		if precedence(node.Opr.Kind) != token.AssignPrec || startOf(node.Y).Line > node.Opr.End.Line {
+			return nil, "", nil
+		}
+
+		head = func(p *printer) { p.operandOf(node.X, token.AssignPrec, false) }
+		return head, node.Opr.Kind.String(), func(p *printer) { p.operandOf(node.Y, token.AssignPrec, true) }
+	}
+
+	return nil, "", nil
+}
+
+func (p *printer) attributes(attrs *ast.AttributeList) {
+	if attrs != nil {
+		p.node(attrs)
+		p.write(" ")
+	}
+}
+
+func (p *printer) infixOp(node *ast.InfixOp) {
+	prec := precedence(node.Opr.Kind)
+
+	p.operandOf(node.X, prec, false)
+
+	// Keep the line break after the operator.
+	if startOf(node.Y).Line > node.Opr.End.Line {
+		p.write(" " + node.Opr.Kind.String())
+		p.indent++
+		p.newline()
+		p.operandOf(node.Y, prec, true)
+		p.indent--
+		return
+	}
+
+	if prec == token.ArrowPrec {
+		// Ranges are written without spaces, e.g. `0..<n`.
+		p.write(node.Opr.Kind.String())
+	} else {
+		p.write(" " + node.Opr.Kind.String() + " ")
+	}
+
+	p.operandOf(node.Y, prec, true)
+}
+
+// Prints the operand of the infix operator. Operators are left
+// associative, so the right operand with the same precedence
+// requires parentheses.
+func (p *printer) operandOf(node ast.Node, prec token.Precedence, right bool) {
+	if infix, ok := node.(*ast.InfixOp); ok {
+		if opPrec := precedence(infix.Opr.Kind); opPrec < prec || right && opPrec == prec {
+			p.write("(")
+			p.node(node)
+			p.write(")")
+			return
+		}
+	}
+
+	p.node(node)
+}
+
+// Prints the operand of the suffix expression, e.g. call or member access.
+func (p *printer) operand(node ast.Node) {
+	switch node.(type) {
+	case *ast.InfixOp, *ast.PrefixOp:
+		p.write("(")
+		p.node(node)
+		p.write(")")
+
+	default:
+		p.node(node)
+	}
+}
+
+//------------------------------------------------
+// Helpers
+//------------------------------------------------
+
+// Returns the start of the node including its attributes.
+func startOf(node ast.Node) token.Loc {
+	var attrs *ast.AttributeList
+
+	switch node := node.(type) {
+	case ast.Decl:
+		attrs = node.Attributes()
+
+	case *ast.Binding:
+		attrs = node.Attrs
+	}
+
+	if attrs != nil {
+		return attrs.Pos()
+	}
+
+	return node.Pos()
+}
+
+func isSingleLine(node ast.Node) bool {
+	return startOf(node).Line == node.LocEnd().Line
+}
+
+// Returns the offset of the end of the line containing the offset.
+func lineEnd(src []byte, offset uint64) uint64 {
+	if i := bytes.IndexByte(src[offset:], '\n'); i >= 0 {
+		return offset + uint64(i)
+	}
+
+	return uint64(len(src))
+}
+
+func precedence(kind ast.OperatorKind) token.Precedence {
+	switch kind {
+	case ast.OperatorMul, ast.OperatorDiv, ast.OperatorMod:
+		return token.MulPrec
+
+	case ast.OperatorAdd, ast.OperatorSub:
+		return token.AddPrec
+
+	case ast.OperatorBitShl, ast.OperatorBitShr:
+		return token.ShiftPrec
+
+	case ast.OperatorBitAnd, ast.OperatorBitOr, ast.OperatorBitXor:
+		return token.BitwisePrec
+
+	case ast.OperatorEq, ast.OperatorNe, ast.OperatorLt, ast.OperatorLe, ast.OperatorGt, ast.OperatorGe:
+		return token.CmpPrec
+
+	case ast.OperatorAnd, ast.OperatorOr:
+		return token.BooleanOpPrec
+
+	case ast.OperatorRangeIncl, ast.OperatorRangeExcl:
+		return token.ArrowPrec
+
+	case ast.OperatorAssign,
+		ast.OperatorAddAndAssign,
+		ast.OperatorSubAndAssign,
+		ast.OperatorMultAndAssign,
+		ast.OperatorDivAndAssign,
+		ast.OperatorModAndAssign:
+		return token.AssignPrec
+
+	default:
+		return token.LowestPrec
+	}
+}
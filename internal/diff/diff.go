@@ -0,0 +1,207 @@
+// Package diff implements line-based unified diffs.
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Number of the unchanged lines displayed around the changes.
+const context = 3
+
+type opKind byte
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+type op struct {
+	kind opKind
+	line string
+}
+
+// Returns the unified diff of the texts or an empty string if
+// they are equal.
+func Unified(oldName, newName string, old, new []byte) string {
+	if bytes.Equal(old, new) {
+		return ""
+	}
+
+	ops := lineDiff(splitLines(old), splitLines(new))
+	buf := strings.Builder{}
+	fmt.Fprintf(&buf, "--- %s\n+++ %s\n", oldName, newName)
+
+	for start := 0; start < len(ops); {
+		// Find the next change.
+		for start < len(ops) && ops[start].kind == opEqual {
+			start++
+		}
+
+		if start == len(ops) {
+			break
+		}
+
+		// Extend the hunk while the changes are close enough.
+		end := start
+		for i := start; i < len(ops); i++ {
+			if ops[i].kind != opEqual {
+				end = i + 1
+			} else if i-end >= 2*context {
+				break
+			}
+		}
+
+		hunkStart := max(start-context, 0)
+		hunkEnd := min(end+context, len(ops))
+		writeHunk(&buf, ops, hunkStart, hunkEnd)
+		start = hunkEnd
+	}
+
+	return buf.String()
+}
+
+func writeHunk(buf *strings.Builder, ops []op, start, end int) {
+	oldLine, newLine := 1, 1
+
+	for _, op := range ops[:start] {
+		if op.kind != opInsert {
+			oldLine++
+		}
+
+		if op.kind != opDelete {
+			newLine++
+		}
+	}
+
+	oldCount, newCount := 0, 0
+
+	for _, op := range ops[start:end] {
+		if op.kind != opInsert {
+			oldCount++
+		}
+
+		if op.kind != opDelete {
+			newCount++
+		}
+	}
+
+	fmt.Fprintf(buf, "@@ -%s +%s @@\n", hunkRange(oldLine, oldCount), hunkRange(newLine, newCount))
+
+	for _, op := range ops[start:end] {
+		switch op.kind {
+		case opEqual:
+			buf.WriteByte(' ')
+
+		case opDelete:
+			buf.WriteByte('-')
+
+		case opInsert:
+			buf.WriteByte('+')
+		}
+
+		buf.WriteString(op.line)
+
+		if !strings.HasSuffix(op.line, "\n") {
+			buf.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+func hunkRange(line, count int) string {
+	if count == 0 {
+		// Empty range refers to the line before it.
+		line--
+	}
+
+	if count == 1 {
+		return fmt.Sprint(line)
+	}
+
+	return fmt.Sprintf("%d,%d", line, count)
+}
+
+// Splits the text into lines. Each line includes the line terminator.
+func splitLines(text []byte) []string {
+	lines := strings.SplitAfter(string(text), "\n")
+
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// Computes the edit script using the longest common subsequence
+// of the lines.
+func lineDiff(a, b []string) []op {
+	// Common prefix and suffix are not included in the table.
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]op, 0, len(a)+len(b))
+
+	for _, line := range a[:prefix] {
+		ops = append(ops, op{opEqual, line})
+	}
+
+	x, y := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	// lcs[i][j] is the length of the LCS of x[i:] and y[j:].
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			ops = append(ops, op{opEqual, x[i]})
+			i++
+			j++
+
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, op{opDelete, x[i]})
+			i++
+
+		default:
+			ops = append(ops, op{opInsert, y[j]})
+			j++
+		}
+	}
+
+	for ; i < len(x); i++ {
+		ops = append(ops, op{opDelete, x[i]})
+	}
+
+	for ; j < len(y); j++ {
+		ops = append(ops, op{opInsert, y[j]})
+	}
+
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, op{opEqual, line})
+	}
+
+	return ops
+}
@@ -0,0 +1,31 @@
+package diff
+
+import "testing"
+
+func TestUnified(t *testing.T) {
+	old := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\n"
+	new := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\n"
+	expected := `--- old
++++ new
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -10,3 +10,4 @@
+ j
+ k
+ l
++m
+`
+
+	if result := Unified("old", "new", []byte(old), []byte(new)); result != expected {
+		t.Fatalf("unexpected diff:\n%s\nexpected:\n%s", result, expected)
+	}
+
+	if result := Unified("old", "new", []byte(old), []byte(old)); result != "" {
+		t.Fatalf("expected empty diff, got:\n%s", result)
+	}
+}
@@ -15,6 +15,9 @@ var ShowLine = true
 // Specifies whether to output debug messages.
 var IsDebug = false
 
+// Specifies whether to output hints (e.g. compilation progress).
+var ShowHints = true
+
 // Reporter is an interface that is used to make the report prettier/clearer.
 //
 // Types implementing this interface must call the functions they need
@@ -25,11 +28,13 @@ type Reporter interface {
 
 // Displays an error report for each of the errors.
 //
-// If the error implements the [Reporter] interface, it will be used instead
-// of the usual Error() function.
+// If the error implements the [Diagnoser] or [Reporter] interface, it will
+// be used instead of the usual Error() function.
 func Errors(errors ...error) {
 	for _, err := range errors {
-		if reporter, ok := err.(Reporter); ok {
+		if _, ok := err.(Diagnoser); ok {
+			Emit(ToDiagnostic(err))
+		} else if reporter, ok := err.(Reporter); ok {
 			reporter.Report()
 		} else {
 			Error(err.Error())
@@ -46,7 +51,7 @@ func Report(kind Kind, args ...any) {
 // debug message.
 //
 // Same as `Report(KindDebug, args...)`.
-func Debug(format string, args ...any) {
+func Debug(args ...any) {
 	Report(KindDebug, args...)
 }
 
@@ -75,10 +80,10 @@ func Warning(args ...any) {
 }
 
 // [Error] is a convenient helper function for reporting an
-// error with a formatted message.
+// error with a message.
 //
 // Same as `Report(KindError, args...)`.
-func Error(format string, args ...any) {
+func Error(args ...any) {
 	Report(KindError, args...)
 }
 
@@ -0,0 +1,122 @@
+package report
+
+import (
+	"embed"
+	"slices"
+	"strings"
+)
+
+// Code is a stable identifier of the diagnostic, e.g. "E0201". The
+// code of the diagnostic doesn't change when its message is changed,
+// so it can be used to search for and to filter the diagnostics.
+//
+// The first 2 digits specify the stage of the compiler that reports
+// the diagnostic: 00 - scanner, 01 - parser, 02..08 - checker,
+// 09 - features that are not implemented yet.
+//
+// Each code has a long-form explanation, see [Explain].
+type Code string
+
+// Scanner codes.
+const (
+	CodeIllegalCharacter   Code = "E0001"
+	CodeUnterminatedString Code = "E0002"
+	CodeInvalidEscape      Code = "E0003"
+	CodeInvalidNumber      Code = "E0004"
+)
+
+// Parser codes.
+const (
+	CodeUnexpectedToken     Code = "E0101"
+	CodeUnclosedBracket     Code = "E0102"
+	CodeMisplacedAttributes Code = "E0103"
+)
+
+// Checker codes of the declarations and names.
+const (
+	CodeUndefinedName      Code = "E0201"
+	CodeAlreadyDefined     Code = "E0202"
+	CodeModuleNotFound     Code = "E0203"
+	CodeImportCycle        Code = "E0204"
+	CodeInvalidDecl        Code = "E0205"
+	CodeInvalidFuncDecl    Code = "E0206"
+	CodeInvalidHarnessFunc Code = "E0207"
+	CodeInvalidTypeDecl    Code = "E0208"
+	CodeUnknownBuiltIn     Code = "E0209"
+	CodeInvalidReturn      Code = "E0210"
+	CodeOutsideLoop        Code = "E0211"
+	CodeUndefinedLabel     Code = "E0212"
+)
+
+// Checker codes of the expressions.
+const (
+	CodeTypeMismatch       Code = "E0301"
+	CodeExpectedType       Code = "E0302"
+	CodeExpectedValue      Code = "E0303"
+	CodeUnknownMember      Code = "E0304"
+	CodeNotCallable        Code = "E0305"
+	CodeInvalidArguments   Code = "E0306"
+	CodeInvalidIndex       Code = "E0307"
+	CodeUndefinedOperator  Code = "E0308"
+	CodeNotAssignable      Code = "E0309"
+	CodeInvalidStructInit  Code = "E0310"
+	CodeNonBoolCondition   Code = "E0311"
+	CodeBranchMismatch     Code = "E0312"
+	CodeInvalidArraySize   Code = "E0313"
+	CodeNotConstant        Code = "E0314"
+	CodeInvalidRange       Code = "E0315"
+	CodeNotIterable        Code = "E0316"
+	CodeInvalidMatch       Code = "E0317"
+	CodeNonExhaustiveMatch Code = "E0318"
+	CodeImmutable          Code = "E0319"
+)
+
+// Features that are not implemented yet.
+const (
+	CodeNotImplemented Code = "E0901"
+)
+
+//go:embed explain/*.md
+var explanations embed.FS
+
+// Returns the long-form explanation of the code in Markdown format.
+// The explanation contains an example of the erroneous code and the
+// fixed code. Returns false if the code is unknown.
+func Explain(code Code) (string, bool) {
+	data, err := explanations.ReadFile("explain/" + string(code) + ".md")
+	if err != nil {
+		return "", false
+	}
+
+	return string(data), true
+}
+
+// Returns the title of the code, i.e. the first line of its
+// explanation without the heading marker and the code.
+func (code Code) Title() string {
+	text, ok := Explain(code)
+	if !ok {
+		return ""
+	}
+
+	title, _, _ := strings.Cut(text, "\n")
+	title = strings.TrimPrefix(title, "# "+string(code))
+	return strings.TrimSpace(strings.TrimPrefix(title, ":"))
+}
+
+// Returns all codes that have an explanation in ascending order.
+func Codes() []Code {
+	entries, err := explanations.ReadDir("explain")
+	if err != nil {
+		panic(err)
+	}
+
+	codes := make([]Code, 0, len(entries))
+
+	for _, entry := range entries {
+		codes = append(codes, Code(strings.TrimSuffix(entry.Name(), ".md")))
+	}
+
+	slices.Sort(codes)
+	return codes
+}
@@ -0,0 +1,412 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/saffage/jet/config"
+	"github.com/saffage/jet/token"
+)
+
+// Format of the reported diagnostics.
+type Format byte
+
+const (
+	// Human-readable colored text. See [Terminal].
+	FormatText Format = iota
+
+	// JSON object with the list of diagnostics.
+	FormatJSON
+
+	// SARIF 2.1.0 log.
+	FormatSARIF
+)
+
+// Returns the format with the specified name ("text", "json" or "sarif").
+func ParseFormat(name string) (Format, error) {
+	switch name {
+	case "text":
+		return FormatText, nil
+
+	case "json":
+		return FormatJSON, nil
+
+	case "sarif":
+		return FormatSARIF, nil
+
+	default:
+		return FormatText, fmt.Errorf("unknown diagnostics format '%s' (expected 'text', 'json' or 'sarif')", name)
+	}
+}
+
+// Diagnostic is a single reported message with an optional location.
+type Diagnostic struct {
+	Kind    Kind
+	Tag     string
+	Message string
+
+	// Stable code of the diagnostic, e.g. "E0201". Empty for the
+	// diagnostics that have no explanation.
+	Code Code
+
+	// Location of the diagnostic. End is inclusive. Zero values
+	// mean that the location is not specified.
+	Start, End token.Loc
+
+	// Notes attached to the diagnostic, e.g. previous declaration.
+	Notes []*Diagnostic
+
+	// Suggested changes of the source code that resolve the diagnostic.
+	Fixes []*Fix
+}
+
+// Fix is a suggested change of the source code. The text in the
+// range is replaced with the new text.
+type Fix struct {
+	// Short description of the change, e.g. "did you mean 'x'?".
+	Message string
+
+	// Range of the replaced text. End is inclusive.
+	Start, End token.Loc
+
+	NewText string
+}
+
+// Diagnoser is implemented by the errors that can be converted
+// to a structured diagnostic.
+type Diagnoser interface {
+	Diagnostic() *Diagnostic
+}
+
+// Converts the error to a diagnostic. If the error doesn't
+// implement [Diagnoser], the diagnostic has no location.
+func ToDiagnostic(err error) *Diagnostic {
+	if diagnoser, ok := err.(Diagnoser); ok {
+		return diagnoser.Diagnostic()
+	}
+
+	return &Diagnostic{Kind: KindError, Message: err.Error()}
+}
+
+// Encoder is a sink that collects the diagnostics and writes
+// them in JSON or SARIF format when flushed. Debug messages
+// and hints are not collected.
+type Encoder struct {
+	Collector
+
+	W      io.Writer
+	Format Format
+
+	// Configuration that contains the file table. Used to get the file
+	// paths of the locations. If nil, the paths are empty.
+	Config *config.Config
+}
+
+func NewEncoder(w io.Writer, format Format) *Encoder {
+	if format == FormatText {
+		panic("text format is not supported by the encoder")
+	}
+
+	return &Encoder{W: w, Format: format}
+}
+
+func (e *Encoder) Report(d *Diagnostic) {
+	if d.Kind != KindDebug && d.Kind != KindHint {
+		e.Collector.Report(d)
+	}
+}
+
+func (e *Encoder) Flush() error {
+	return Write(e.W, e.Format, e.Config, e.Diagnostics())
+}
+
+// Writes the diagnostics in the specified format. The file paths are
+// taken from the configuration, which can be nil.
+func Write(w io.Writer, format Format, cfg *config.Config, diagnostics []*Diagnostic) error {
+	var v any
+
+	switch format {
+	case FormatText:
+		return nil
+
+	case FormatJSON:
+		v = jsonLog{Diagnostics: jsonDiagnostics(cfg, diagnostics)}
+
+	case FormatSARIF:
+		v = sarifLogOf(cfg, diagnostics)
+
+	default:
+		panic("unreachable")
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(v)
+}
+
+//------------------------------------------------
+// JSON
+//------------------------------------------------
+
+type jsonLog struct {
+	Diagnostics []jsonDiagnostic `json:"diagnostics"`
+}
+
+type jsonDiagnostic struct {
+	Severity string           `json:"severity"`
+	Tag      string           `json:"tag,omitempty"`
+	Code     Code             `json:"code,omitempty"`
+	Message  string           `json:"message"`
+	Location *jsonLocation    `json:"location,omitempty"`
+	Notes    []jsonDiagnostic `json:"notes,omitempty"`
+	Fixes    []jsonFix        `json:"fixes,omitempty"`
+}
+
+type jsonFix struct {
+	Message  string       `json:"message"`
+	Location jsonLocation `json:"location"`
+	NewText  string       `json:"newText"`
+}
+
+// End position is exclusive.
+type jsonLocation struct {
+	File  string       `json:"file"`
+	Start jsonPosition `json:"start"`
+	End   jsonPosition `json:"end"`
+}
+
+type jsonPosition struct {
+	Line   uint32 `json:"line"`
+	Column uint32 `json:"column"`
+	Offset uint64 `json:"offset"`
+}
+
+func jsonDiagnostics(cfg *config.Config, diagnostics []*Diagnostic) []jsonDiagnostic {
+	result := make([]jsonDiagnostic, 0, len(diagnostics))
+
+	for _, d := range diagnostics {
+		jd := jsonDiagnostic{
+			Severity: d.Kind.String(),
+			Tag:      d.Tag,
+			Code:     d.Code,
+			Message:  d.Message,
+		}
+
+		if d.Start.IsValid() {
+			loc := jsonLocationOf(cfg, d.Start, d.End)
+			jd.Location = &loc
+		}
+
+		if len(d.Notes) > 0 {
+			jd.Notes = jsonDiagnostics(cfg, d.Notes)
+		}
+
+		for _, fix := range d.Fixes {
+			jd.Fixes = append(jd.Fixes, jsonFix{
+				Message:  fix.Message,
+				Location: jsonLocationOf(cfg, fix.Start, fix.End),
+				NewText:  fix.NewText,
+			})
+		}
+
+		result = append(result, jd)
+	}
+
+	return result
+}
+
+func jsonLocationOf(cfg *config.Config, start, end token.Loc) jsonLocation {
+	return jsonLocation{
+		File:  filePath(cfg, start.FileID),
+		Start: jsonPosition{start.Line, start.Char, start.Offset},
+		End:   jsonPosition{end.Line, end.Char + 1, end.Offset + 1},
+	}
+}
+
+//------------------------------------------------
+// SARIF
+//------------------------------------------------
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type sarifResult struct {
+	RuleID           string          `json:"ruleId,omitempty"`
+	Level            string          `json:"level"`
+	Message          sarifMessage    `json:"message"`
+	Locations        []sarifLocation `json:"locations,omitempty"`
+	RelatedLocations []sarifLocation `json:"relatedLocations,omitempty"`
+	Fixes            []sarifFix      `json:"fixes,omitempty"`
+}
+
+type sarifFix struct {
+	Description     sarifMessage          `json:"description"`
+	ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+}
+
+type sarifArtifactChange struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Replacements     []sarifReplacement    `json:"replacements"`
+}
+
+type sarifReplacement struct {
+	DeletedRegion   sarifRegion          `json:"deletedRegion"`
+	InsertedContent sarifArtifactContent `json:"insertedContent"`
+}
+
+type sarifArtifactContent struct {
+	Text string `json:"text"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	ID               *int                   `json:"id,omitempty"`
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	Message          *sarifMessage          `json:"message,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// End column is exclusive.
+type sarifRegion struct {
+	StartLine   uint32 `json:"startLine"`
+	StartColumn uint32 `json:"startColumn"`
+	EndLine     uint32 `json:"endLine"`
+	EndColumn   uint32 `json:"endColumn"`
+}
+
+func sarifLogOf(cfg *config.Config, diagnostics []*Diagnostic) sarifLog {
+	results := make([]sarifResult, 0, len(diagnostics))
+
+	for _, d := range diagnostics {
+		result := sarifResult{
+			RuleID:  sarifRuleID(d),
+			Level:   sarifLevel(d.Kind),
+			Message: sarifMessage{d.Message},
+		}
+
+		if loc := sarifPhysicalLocationOf(cfg, d); loc != nil {
+			result.Locations = []sarifLocation{{PhysicalLocation: loc}}
+		}
+
+		for i, note := range d.Notes {
+			id := i
+			result.RelatedLocations = append(result.RelatedLocations, sarifLocation{
+				ID:               &id,
+				PhysicalLocation: sarifPhysicalLocationOf(cfg, note),
+				Message:          &sarifMessage{note.Message},
+			})
+		}
+
+		for _, fix := range d.Fixes {
+			result.Fixes = append(result.Fixes, sarifFix{
+				Description: sarifMessage{fix.Message},
+				ArtifactChanges: []sarifArtifactChange{{
+					ArtifactLocation: sarifArtifactLocation{URI: filePath(cfg, fix.Start.FileID)},
+					Replacements: []sarifReplacement{{
+						DeletedRegion:   sarifRegionOf(fix.Start, fix.End),
+						InsertedContent: sarifArtifactContent{fix.NewText},
+					}},
+				}},
+			})
+		}
+
+		results = append(results, result)
+	}
+
+	return sarifLog{
+		Schema:  sarifSchema,
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool:    sarifTool{sarifDriver{Name: "jet", Version: config.Version}},
+			Results: results,
+		}},
+	}
+}
+
+func sarifPhysicalLocationOf(cfg *config.Config, d *Diagnostic) *sarifPhysicalLocation {
+	if !d.Start.IsValid() {
+		return nil
+	}
+
+	return &sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{URI: filePath(cfg, d.Start.FileID)},
+		Region:           sarifRegionOf(d.Start, d.End),
+	}
+}
+
+func sarifRegionOf(start, end token.Loc) sarifRegion {
+	return sarifRegion{
+		StartLine:   start.Line,
+		StartColumn: start.Char,
+		EndLine:     end.Line,
+		EndColumn:   end.Char + 1,
+	}
+}
+
+// Code is used as the rule identifier, so the results can be
+// suppressed by the code. Diagnostics without code use the tag.
+func sarifRuleID(d *Diagnostic) string {
+	if d.Code != "" {
+		return string(d.Code)
+	}
+
+	return d.Tag
+}
+
+func sarifLevel(kind Kind) string {
+	switch kind {
+	case KindError:
+		return "error"
+
+	case KindWarning:
+		return "warning"
+
+	default:
+		return "note"
+	}
+}
+
+func filePath(cfg *config.Config, fileID config.FileID) string {
+	if cfg == nil {
+		return ""
+	}
+
+	if fileInfo, ok := cfg.Files[fileID]; ok {
+		return filepath.ToSlash(fileInfo.Path)
+	}
+
+	return ""
+}
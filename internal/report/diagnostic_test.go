@@ -0,0 +1,131 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/saffage/jet/config"
+	"github.com/saffage/jet/token"
+)
+
+type testError struct{}
+
+func (testError) Error() string { return "error" }
+
+func (testError) Diagnostic() *Diagnostic {
+	return &Diagnostic{
+		Kind:    KindError,
+		Tag:     "test",
+		Code:    CodeUndefinedName,
+		Message: "error",
+		Start:   token.Loc{FileID: 1, Line: 1, Char: 2},
+		End:     token.Loc{FileID: 1, Line: 1, Char: 4},
+		Notes:   []*Diagnostic{{Kind: KindNote, Tag: "test", Message: "note"}},
+		Fixes: []*Fix{{
+			Message: "fix",
+			Start:   token.Loc{FileID: 1, Line: 1, Char: 2},
+			End:     token.Loc{FileID: 1, Line: 1, Char: 3},
+			NewText: "x",
+		}},
+	}
+}
+
+func TestDiagnosticsJSON(t *testing.T) {
+	encoder := NewEncoder(&bytes.Buffer{}, FormatJSON)
+	defer SetSink(SetSink(encoder))
+
+	Errors(testError{})
+	Hintf("hints are not collected")
+	Warningf("warning")
+
+	cfg := config.New()
+	cfg.Files[1] = config.FileInfo{Path: "main.jet"}
+
+	buf := bytes.Buffer{}
+	if err := Write(&buf, FormatJSON, cfg, encoder.Diagnostics()); err != nil {
+		t.Fatal(err)
+	}
+
+	log := jsonLog{}
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(log.Diagnostics) != 2 {
+		t.Fatalf("expected 2 diagnostics, have %d", len(log.Diagnostics))
+	}
+
+	d := log.Diagnostics[0]
+	if d.Severity != "error" || d.Tag != "test" || d.Code != CodeUndefinedName || d.Location == nil {
+		t.Errorf("unexpected diagnostic: %+v", d)
+	} else if d.Location.File != "main.jet" || d.Location.Start.Column != 2 || d.Location.End.Column != 5 {
+		t.Errorf("unexpected location: %+v", *d.Location)
+	}
+
+	if len(d.Notes) != 1 || d.Notes[0].Message != "note" {
+		t.Errorf("expected the note to be attached to the error: %+v", d.Notes)
+	}
+
+	if len(d.Fixes) != 1 || d.Fixes[0].NewText != "x" || d.Fixes[0].Location.End.Column != 4 {
+		t.Errorf("expected the fix to be attached to the error: %+v", d.Fixes)
+	}
+
+	if log.Diagnostics[1].Severity != "warning" {
+		t.Errorf("unexpected diagnostic: %+v", log.Diagnostics[1])
+	}
+}
+
+func TestLimit(t *testing.T) {
+	collector := &Collector{}
+	limit := NewLimit(&Filter{
+		Sink: collector,
+		Func: func(d *Diagnostic) bool { return d.Kind != KindWarning },
+	}, 2)
+	defer SetSink(SetSink(limit))
+
+	for range 4 {
+		Errorf("error")
+		Warningf("warning")
+	}
+
+	if err := limit.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if limit.Errors() != 4 {
+		t.Errorf("expected 4 errors, have %d", limit.Errors())
+	}
+
+	diagnostics := collector.Diagnostics()
+	if len(diagnostics) != 3 {
+		t.Fatalf("expected 3 diagnostics, have %d", len(diagnostics))
+	}
+
+	if d := diagnostics[2]; d.Kind != KindNote || d.Message != "2 more error(s) not shown (the limit is 2)" {
+		t.Errorf("unexpected diagnostic: %+v", d)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	codes := Codes()
+	if len(codes) == 0 {
+		t.Fatal("expected the explained codes")
+	}
+
+	for _, code := range codes {
+		text, ok := Explain(code)
+		if !ok || !strings.HasPrefix(text, "# "+string(code)+": ") {
+			t.Errorf("explanation of %s must start with its code", code)
+		}
+
+		if code.Title() == "" {
+			t.Errorf("explanation of %s has no title", code)
+		}
+	}
+
+	if _, ok := Explain("E9999"); ok {
+		t.Error("unexpected explanation of an unknown code")
+	}
+}
@@ -0,0 +1,168 @@
+package report
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Sink receives the reported diagnostics.
+//
+// Implementations must be safe for concurrent use, because the
+// modules are checked concurrently.
+type Sink interface {
+	Report(d *Diagnostic)
+}
+
+// Flusher is implemented by the sinks that buffer the diagnostics.
+type Flusher interface {
+	Flush() error
+}
+
+var (
+	sinkMu sync.RWMutex
+	sink   Sink = NewTerminal()
+)
+
+// Sets the sink that receives all reported diagnostics and
+// returns the previous one. The default sink is [Terminal].
+func SetSink(s Sink) Sink {
+	sinkMu.Lock()
+	defer sinkMu.Unlock()
+
+	prev := sink
+	sink = s
+	return prev
+}
+
+// Sends the diagnostic to the current sink. Debug messages and
+// hints are dropped if [IsDebug] and [ShowHints] are not set.
+func Emit(d *Diagnostic) {
+	if d.Kind == KindDebug && !IsDebug || d.Kind == KindHint && !ShowHints {
+		return
+	}
+
+	sinkMu.RLock()
+	defer sinkMu.RUnlock()
+
+	sink.Report(d)
+}
+
+// Flushes the current sink if it implements [Flusher].
+func Flush() {
+	sinkMu.RLock()
+	defer sinkMu.RUnlock()
+
+	if flusher, ok := sink.(Flusher); ok {
+		if err := flusher.Flush(); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Collector is a sink that stores the diagnostics.
+type Collector struct {
+	mu          sync.Mutex
+	diagnostics []*Diagnostic
+}
+
+func (c *Collector) Report(d *Diagnostic) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.diagnostics = append(c.diagnostics, d)
+}
+
+// Returns the collected diagnostics.
+func (c *Collector) Diagnostics() []*Diagnostic {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.diagnostics
+}
+
+// Filter is a sink that forwards only the diagnostics
+// for which the function returns true.
+type Filter struct {
+	Sink Sink
+	Func func(d *Diagnostic) bool
+}
+
+func (f *Filter) Report(d *Diagnostic) {
+	if f.Func(d) {
+		f.Sink.Report(d)
+	}
+}
+
+func (f *Filter) Flush() error {
+	if flusher, ok := f.Sink.(Flusher); ok {
+		return flusher.Flush()
+	}
+
+	return nil
+}
+
+// Limit is a sink that counts the errors and forwards at most
+// 'Max' of them. Diagnostics of other kinds are always forwarded.
+// If 'Max' is less than 1, the number of errors is not limited.
+type Limit struct {
+	Sink Sink
+	Max  int
+
+	mu      sync.Mutex
+	errors  int
+	dropped int
+}
+
+func NewLimit(sink Sink, max int) *Limit {
+	return &Limit{Sink: sink, Max: max}
+}
+
+func (l *Limit) Report(d *Diagnostic) {
+	if d.Kind == KindError {
+		l.mu.Lock()
+		l.errors++
+		drop := l.Max > 0 && l.errors > l.Max
+
+		if drop {
+			l.dropped++
+		}
+
+		l.mu.Unlock()
+
+		if drop {
+			return
+		}
+	}
+
+	l.Sink.Report(d)
+}
+
+// Returns the number of the reported errors, including the dropped ones.
+func (l *Limit) Errors() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.errors
+}
+
+// Reports the number of the dropped errors and flushes the
+// underlying sink.
+func (l *Limit) Flush() error {
+	l.mu.Lock()
+	dropped := l.dropped
+	l.dropped = 0
+	l.mu.Unlock()
+
+	if dropped > 0 {
+		l.Sink.Report(&Diagnostic{
+			Kind:    KindNote,
+			Message: fmt.Sprintf("%d more error(s) not shown (the limit is %d)", dropped, l.Max),
+		})
+	}
+
+	if flusher, ok := l.Sink.(Flusher); ok {
+		return flusher.Flush()
+	}
+
+	return nil
+}
@@ -0,0 +1,259 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+	"github.com/saffage/jet/config"
+	"github.com/saffage/jet/scanner/base"
+	"github.com/saffage/jet/token"
+)
+
+// Terminal is a sink that displays the diagnostics as human-readable
+// text immediately. Errors and debug messages are written to 'Stderr',
+// other diagnostics are written to 'Stdout'.
+type Terminal struct {
+	Stdout, Stderr io.Writer
+
+	// Configuration that contains the file table. Used to display the
+	// file paths and the source code. If nil, they are not displayed.
+	Config *config.Config
+
+	mu sync.Mutex
+}
+
+func NewTerminal() *Terminal {
+	return &Terminal{Stdout: os.Stdout, Stderr: os.Stderr}
+}
+
+func (t *Terminal) Report(d *Diagnostic) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.display(d)
+
+	for _, note := range d.Notes {
+		t.display(note)
+	}
+
+	for _, fix := range d.Fixes {
+		t.displayFix(fix, t.writer(d.Kind))
+	}
+}
+
+func (t *Terminal) display(d *Diagnostic) {
+	message := fmt.Sprintf("%s %s", d.Kind.TaggedLabel(d.Tag), d.Message)
+
+	if d.Code != "" {
+		message += fmt.Sprintf(" [%s]", d.Code)
+	}
+
+	if d.Start.IsValid() {
+		message += "\n" + t.formatLoc(d.Start)
+
+		if fileInfo, ok := t.config().Files[d.Start.FileID]; ok && fileInfo.Buf != nil {
+			message += generateLine(d.Kind, d.Start, d.End, fileInfo.Buf.Bytes())
+		}
+	}
+
+	if _, err := fmt.Fprintln(t.writer(d.Kind), message); err != nil {
+		panic(err)
+	}
+}
+
+// Displays the fix as a hint followed by the changed line of the
+// source code. The fix is written to the same writer as the
+// diagnostic, so they are not mixed with other messages.
+func (t *Terminal) displayFix(fix *Fix, w io.Writer) {
+	message := fmt.Sprintf("%s %s", KindHint.Label(), fix.Message)
+
+	if fileInfo, ok := t.config().Files[fix.Start.FileID]; ok && fileInfo.Buf != nil {
+		message += generateFixedLine(fix, fileInfo.Buf.Bytes())
+	}
+
+	if _, err := fmt.Fprintln(w, message); err != nil {
+		panic(err)
+	}
+}
+
+func (t *Terminal) writer(kind Kind) io.Writer {
+	switch kind {
+	case KindNote, KindHint, KindWarning:
+		return t.Stdout
+
+	case KindDebug, KindError:
+		return t.Stderr
+
+	default:
+		panic("unreachable")
+	}
+}
+
+func (t *Terminal) config() *config.Config {
+	if t.Config != nil {
+		return t.Config
+	}
+
+	return noConfig
+}
+
+// Configuration with the empty file table.
+var noConfig = config.New()
+
+func (t *Terminal) formatLoc(loc token.Loc) string {
+	s := fmt.Sprintf("%d", loc.Line)
+
+	if loc.Char > 0 {
+		s += fmt.Sprintf(":%d", loc.Char)
+	}
+
+	if fileInfo, ok := t.config().Files[loc.FileID]; ok && fileInfo.Path != "" {
+		s = fileInfo.Path + ":" + s
+	}
+
+	if UseColors {
+		return fmt.Sprintf("%s%s %s",
+			strings.Repeat(" ", numLen(int(loc.Line))),
+			lineNumStyle.Sprint("-->"),
+			color.CyanString(s),
+		)
+	}
+
+	return fmt.Sprintf("%s--> %s", strings.Repeat(" ", numLen(int(loc.Line))), s)
+}
+
+func generateLine(kind Kind, start, end token.Loc, buffer []byte) string {
+	if !ShowLine || start.FileID == 0 || start.Line == 0 {
+		return ""
+	}
+	var (
+		lineContent  = base.New(buffer, start.FileID).GetLine(int(start.Line))
+		lineNumStr   = fmt.Sprintf("%d", start.Line)
+		emptyLineNum = lineNum(strings.Repeat(" ", numLen(int(start.Line))))
+		leftBound    = int(start.Char) - 1
+		rightBound   = int(end.Char) - 1
+		buf          = strings.Builder{}
+	)
+	if end.Line > start.Line {
+		// TODO capture more lines?
+		rightBound = len(lineContent)
+	}
+
+	kindColor := *kind.Color()
+	kindColor.Add(color.Underline)
+
+	buf.WriteByte('\n')
+	buf.WriteString(lineNum(lineNumStr))
+	buf.WriteString(applyColorInRange(
+		&kindColor,
+		lineContent,
+		int(leftBound),
+		int(rightBound),
+	))
+	buf.WriteByte('\n')
+
+	// Tabulation has a variable length, so you need to
+	// keep them in a string there.
+	underlineLen := max(1, rightBound-leftBound+1)
+	underlineLine := strings.Builder{}
+	underlineLine.Grow(leftBound + underlineLen)
+	for _, c := range lineContent[:leftBound] {
+		if c == '\t' {
+			underlineLine.WriteRune(c)
+		} else {
+			underlineLine.WriteByte(' ')
+		}
+	}
+
+	if UseColors {
+		underlineLine.WriteString(kind.Color().Sprintf(
+			strings.Repeat(string(underlineChar(kind)), underlineLen),
+		))
+	} else {
+		underlineLine.WriteString(
+			strings.Repeat(string(underlineChar(kind)), underlineLen),
+		)
+	}
+
+	buf.WriteString(emptyLineNum)
+	buf.WriteString(underlineLine.String())
+	return buf.String()
+}
+
+// Returns the line of the fix with the replaced text. Fixes that
+// span multiple lines are not displayed.
+func generateFixedLine(fix *Fix, buffer []byte) string {
+	if !ShowLine || fix.Start.Line == 0 || fix.Start.Line != fix.End.Line {
+		return ""
+	}
+
+	lineContent := base.New(buffer, fix.Start.FileID).GetLine(int(fix.Start.Line))
+	leftBound, rightBound := int(fix.Start.Char)-1, int(fix.End.Char)
+
+	if leftBound < 0 || rightBound > len(lineContent) || leftBound > rightBound {
+		return ""
+	}
+
+	newText := fix.NewText
+	if UseColors {
+		newText = KindHint.Color().Sprint(newText)
+	}
+
+	return "\n" + lineNum(fmt.Sprintf("%d", fix.Start.Line)) +
+		lineContent[:leftBound] + newText + lineContent[rightBound:]
+}
+
+func lineNum(text string) string {
+	if UseColors {
+		return lineNumStyle.Sprintf("%s |", text)
+	}
+	return text + " |"
+}
+
+func applyColorInRange(color *color.Color, text string, a, b int) string {
+	if !UseColors {
+		return text
+	}
+	if len(text) == 0 {
+		return ""
+	}
+	maxIdx := len(text) - 1
+	textBefore, textAfter := text[:max(0, min(a-1, maxIdx)+1)], ""
+	if b < maxIdx {
+		textAfter = text[b+1:]
+	}
+	return textBefore + color.Sprint(text[a:min(b, maxIdx)+1]) + textAfter
+}
+
+func underlineChar(kind Kind) rune {
+	switch kind {
+	case KindDebug:
+		return '-'
+
+	case KindNote, KindHint, KindWarning:
+		return '^'
+
+	case KindError:
+		return '~'
+
+	default:
+		panic("unreachable")
+	}
+}
+
+func numLen(num int) (len int) {
+	if num <= 0 {
+		len = 1
+	}
+	for num != 0 {
+		num /= 10
+		len += 1
+	}
+	return len
+}
+
+var lineNumStyle = color.New(color.Bold, color.FgHiGreen)
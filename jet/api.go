@@ -0,0 +1,298 @@
+package jet
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/saffage/jet/config"
+	"github.com/saffage/jet/internal/report"
+	"github.com/saffage/jet/lsp"
+)
+
+// Sinks created by [ProcessArgs].
+var (
+	terminal *report.Terminal
+	encoder  *report.Encoder
+)
+
+// Sets the configuration used to display locations in the reports.
+func useConfig(cfg *config.Config) {
+	if terminal != nil {
+		terminal.Config = cfg
+	}
+
+	if encoder != nil {
+		encoder.Config = cfg
+	}
+}
+
+// Limits the number of the displayed errors. Encoded diagnostics are
+// consumed by tools, so they are never limited.
+func limitErrors(sink report.Sink, max int) report.Sink {
+	if _, ok := sink.(*report.Encoder); ok {
+		return sink
+	}
+
+	return report.NewLimit(sink, max)
+}
+
+// Processes the command line arguments and returns the exit code.
+//
+// The caller must call [report.Flush] to output the buffered
+// diagnostics (e.g. in JSON format).
+func ProcessArgs(args []string) int {
+	if err := config.ParseArgs(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			config.Cmd.PrintHelp(os.Stdout)
+			return 0
+		}
+
+		report.Errorf(err.Error())
+		return 1
+	}
+
+	report.IsDebug = config.FlagDebug
+
+	format, err := report.ParseFormat(config.FlagDiagnostics)
+	if err != nil {
+		report.Errorf(err.Error())
+		return 1
+	}
+
+	terminal = report.NewTerminal()
+	sink := report.Sink(terminal)
+	if format != report.FormatText {
+		// Encoded diagnostics must not be mixed with the output of
+		// the command, so they are never written to stdout.
+		w := io.Writer(os.Stderr)
+
+		if config.FlagDiagnosticsFile != "" {
+			// The file is closed on exit.
+			f, err := os.Create(config.FlagDiagnosticsFile)
+			if err != nil {
+				report.Errorf("cannot create diagnostics file: %s", err.Error())
+				return 1
+			}
+
+			w = f
+		}
+
+		encoder = report.NewEncoder(w, format)
+		sink = encoder
+	}
+
+	report.SetSink(sink)
+
+	switch config.Cmd.Name {
+	case "help":
+		return help(config.Args)
+
+	case "explain":
+		return explain(config.Args)
+
+	case "version":
+		return version()
+
+	case "lsp":
+		return serveLSP()
+
+	case "repl":
+		// Compiler messages must not be mixed with the REPL output.
+		report.ShowHints = false
+		compiler := &Compiler{
+			LibPath:     config.CoreLibPath(nil),
+			TraceParser: config.FlagTraceParser,
+		}
+		return runREPL(compiler, os.Stdin, os.Stdout)
+
+	case "fmt":
+		report.SetSink(limitErrors(sink, config.FlagMaxErrors))
+		return formatFiles(config.Args)
+
+	case "run", "test", "bench", "doc":
+		// Compiler messages must not be mixed with the program output
+		// or the generated documentation.
+		report.ShowHints = false
+	}
+
+	if config.Cmd.NotImplemented {
+		report.Errorf("command '%s' is not yet implemented", config.Cmd.Name)
+		return 1
+	}
+
+	path := ""
+	if len(config.Args) > 0 {
+		path = config.Args[0]
+	}
+
+	project, ok := loadProject(path)
+	if !ok {
+		return 1
+	}
+
+	if path == "" {
+		path = project.Main
+	}
+
+	compiler := &Compiler{
+		LibPath:          config.CoreLibPath(project),
+		Project:          project,
+		TraceParser:      config.FlagTraceParser,
+		DumpCheckerState: config.FlagDumpCheckerState,
+	}
+	session := compiler.NewSession()
+	session.Config.MaxErrors = config.FlagMaxErrors
+	report.SetSink(limitErrors(sink, session.Config.MaxErrors))
+	useConfig(session.Config)
+
+	if !loadMainFile(session, path) {
+		return 1
+	}
+
+	switch config.Cmd.Name {
+	case "tokens":
+		return printTokens(session.Config, config.MainFileID)
+
+	case "ast":
+		return printAST(session.Config, config.MainFileID)
+	}
+
+	return process(session)
+}
+
+// Searches for the project manifest starting from the directory of
+// the specified file. If the path is empty, the manifest is searched
+// starting from the current directory and is required.
+func loadProject(path string) (*config.Project, bool) {
+	dir := "."
+	if path != "" {
+		dir = filepath.Dir(path)
+	}
+
+	projectPath := config.FindProject(dir)
+	if projectPath == "" {
+		if path == "" {
+			report.Errorf("expected filename or '%s' in the current directory", config.ProjectFileName)
+			return nil, false
+		}
+
+		return nil, true
+	}
+
+	project, err := config.LoadProject(projectPath)
+	if err != nil {
+		report.Errorf(err.Error())
+		return nil, false
+	}
+
+	report.Debugf("using project '%s' from '%s'", project.Name, projectPath)
+	return project, true
+}
+
+// Reads the specified file and sets it as the main module.
+func loadMainFile(session *Session, path string) bool {
+	path = filepath.Clean(path)
+	stat, err := os.Stat(path)
+	if err != nil {
+		report.Errorf(err.Error())
+		return false
+	}
+
+	if !stat.Mode().IsRegular() {
+		report.Errorf("'%s' is not a file", path)
+		return false
+	}
+
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		report.TaggedErrorf("internal", "while reading file '%s': %s", path, err.Error())
+		panic(err)
+	}
+
+	if err := session.SetMainFile(path, buf); err != nil {
+		report.Errorf(err.Error())
+		return false
+	}
+
+	report.Debugf("set file '%s' as main module", path)
+	return true
+}
+
+func help(args []string) int {
+	switch len(args) {
+	case 0:
+		config.PrintUsage(os.Stdout)
+
+	case 1:
+		cmd := config.LookupCommand(args[0])
+		if cmd == nil {
+			report.Errorf("unknown command '%s'; run 'jet help' for usage", args[0])
+			return 1
+		}
+
+		cmd.PrintHelp(os.Stdout)
+
+	default:
+		report.Errorf("expected at most 1 command name")
+		return 1
+	}
+
+	return 0
+}
+
+func explain(args []string) int {
+	switch len(args) {
+	case 0:
+		for _, code := range report.Codes() {
+			fmt.Printf("%s  %s\n", code, code.Title())
+		}
+
+	case 1:
+		text, ok := report.Explain(report.Code(strings.ToUpper(args[0])))
+		if !ok {
+			report.Errorf("unknown code '%s'; run 'jet explain' for the list of codes", args[0])
+			return 1
+		}
+
+		fmt.Print(text)
+
+	default:
+		report.Errorf("expected at most 1 code")
+		return 1
+	}
+
+	return 0
+}
+
+func serveLSP() int {
+	// The standard output is used by the protocol.
+	report.ShowHints = false
+	report.SetSink(&report.Terminal{Stdout: os.Stderr, Stderr: os.Stderr})
+
+	server := lsp.NewServer(config.FlagCoreLibPath)
+	if err := server.Serve(os.Stdin, os.Stdout); err != nil {
+		report.Errorf(err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func version() int {
+	fmt.Printf("jet %s\n", config.Version)
+
+	libPath := config.CoreLibPath(nil)
+	libVersion, err := config.CoreLibVersion(libPath)
+	if err != nil {
+		report.Warningf("cannot determine the core library version: %s", err.Error())
+		return 0
+	}
+
+	fmt.Printf("core library %s (%s)\n", libVersion, libPath)
+	return 0
+}
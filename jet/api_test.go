@@ -0,0 +1,67 @@
+package jet
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/saffage/jet/config"
+	"github.com/saffage/jet/internal/report"
+)
+
+func TestDiagnosticsNotLimited(t *testing.T) {
+	exe, cmd, args, runArgs := config.Exe, config.Cmd, config.Args, config.RunArgs
+	prev := report.SetSink(report.NewTerminal())
+	t.Cleanup(func() {
+		config.Exe, config.Cmd, config.Args, config.RunArgs = exe, cmd, args, runArgs
+		config.FlagDiagnostics, config.FlagDiagnosticsFile = "text", ""
+		terminal, encoder = nil, nil
+		report.SetSink(prev)
+	})
+
+	dir := writeFiles(t, map[string]string{
+		"Main.jet": "func a() { x1 }\n\nfunc b() { x2 }\n\nfunc c() { x3 }\n\nfunc d() { x4 }\n\nfunc main() { x5 }\n",
+	})
+	file := filepath.Join(dir, "diagnostics.json")
+
+	exitCode := ProcessArgs([]string{
+		"jet", "check",
+		"-lib_path", filepath.Join("..", "lib"),
+		"-diagnostics", "json",
+		"-diagnostics_file", file,
+		filepath.Join(dir, "Main.jet"),
+	})
+	report.Flush()
+
+	if exitCode != 1 {
+		t.Errorf("expected exit code 1, have %d", exitCode)
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	log := struct {
+		Diagnostics []struct {
+			Severity string      `json:"severity"`
+			Code     report.Code `json:"code"`
+		} `json:"diagnostics"`
+	}{}
+
+	if err := json.Unmarshal(content, &log); err != nil {
+		t.Fatal(err)
+	}
+
+	// The default limit of the displayed errors is 3.
+	if len(log.Diagnostics) != 5 {
+		t.Fatalf("expected 5 diagnostics, have %d: %s", len(log.Diagnostics), content)
+	}
+
+	for _, d := range log.Diagnostics {
+		if d.Severity != "error" || d.Code != report.CodeUndefinedName {
+			t.Errorf("unexpected diagnostic: %+v", d)
+		}
+	}
+}
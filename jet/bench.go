@@ -0,0 +1,34 @@
+package jet
+
+import (
+	"github.com/saffage/jet/config"
+	"github.com/saffage/jet/internal/report"
+)
+
+// Builds and runs the benchmarks of the main module selected by the
+// '-run' flag. Returns the exit code of the benchmark executable.
+func runBenchmarks(session *Session) int {
+	benchmarks, ok := selectFuncs(session.Module.Benchmarks, config.FlagRun)
+	if !ok {
+		return 1
+	}
+
+	if len(benchmarks) == 0 {
+		report.Warningf("no benchmarks to run")
+		return 0
+	}
+
+	if config.FlagBenchTime <= 0 {
+		report.Errorf("benchmark time must be positive")
+		return 1
+	}
+
+	cfg := session.Config
+	cFiles, errs := session.EmitBenchmarks(outputDir(cfg, config.MainFileID), benchmarks, config.FlagBenchTime)
+	if len(errs) != 0 {
+		report.Errors(errs...)
+		return 1
+	}
+
+	return buildAndRun(cfg, cFiles, session.Module.Name()+"_bench"+exeSuffix())
+}
@@ -0,0 +1,146 @@
+package jet
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/saffage/jet/config"
+	"github.com/saffage/jet/internal/report"
+)
+
+// Compiles every C file into an object file and links them
+// into the executable specified by 'output'.
+//
+// Flags from the project manifest precede the command line flags.
+func build(cfg *config.Config, cFiles []string, output string) bool {
+	cc := cCompiler()
+	cflags := []string{}
+	ldflags := []string{}
+
+	if cfg.Project != nil {
+		cflags = append(cflags, cfg.Project.CompilerFlags()...)
+		ldflags = append(ldflags, cfg.Project.LDFlags...)
+	}
+
+	cflags = append(cflags, strings.Fields(config.FlagCFlags)...)
+	ldflags = append(ldflags, strings.Fields(config.FlagLDFlags)...)
+	objFiles := make([]string, 0, len(cFiles))
+
+	report.Hintf("build '%s'...", output)
+
+	for _, cFile := range cFiles {
+		objFile := strings.TrimSuffix(cFile, ".c") + ".o"
+		args := append([]string{"-c", cFile, "-o", objFile}, cflags...)
+
+		if !runCC(cc, args) {
+			return false
+		}
+
+		objFiles = append(objFiles, objFile)
+	}
+
+	args := append(objFiles, "-o", output)
+	args = append(args, ldflags...)
+
+	return runCC(cc, args)
+}
+
+// Returns the C compiler command specified by the user, split into
+// words, so the value can contain arguments (e.g. 'ccache gcc' or
+// 'zig cc').
+//
+// Order: '--cc' flag, 'CC' environment variable, 'cc'.
+func cCompiler() []string {
+	if cc := strings.Fields(config.FlagCC); len(cc) > 0 {
+		return cc
+	}
+
+	if cc := strings.Fields(os.Getenv("CC")); len(cc) > 0 {
+		return cc
+	}
+
+	return []string{"cc"}
+}
+
+func exeSuffix() string {
+	if runtime.GOOS == "windows" {
+		return ".exe"
+	}
+
+	return ""
+}
+
+func runCC(cc []string, args []string) bool {
+	args = append(append([]string{}, cc[1:]...), args...)
+	report.TaggedDebugf("cc", "%s %s", cc[0], strings.Join(args, " "))
+
+	output := bytes.Buffer{}
+	cmd := exec.Command(cc[0], args...)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	err := cmd.Run()
+	relayCCDiagnostics(output.String())
+
+	if err != nil {
+		exitErr := (*exec.ExitError)(nil)
+
+		if errors.As(err, &exitErr) {
+			report.TaggedErrorf("cc", "C compiler exited with status %d", exitErr.ExitCode())
+		} else {
+			report.TaggedErrorf("cc", "cannot run C compiler '%s': %s", cc[0], err.Error())
+		}
+
+		return false
+	}
+
+	return true
+}
+
+var (
+	// file:line:column: kind: message
+	ccDiagnosticWithLoc = regexp.MustCompile(`^(.+?):(\d+):(\d+): (fatal error|error|warning|note): (.*)$`)
+
+	// file: kind: message
+	ccDiagnostic = regexp.MustCompile(`^(.+?): (fatal error|error|warning|note): (.*)$`)
+)
+
+// Reports the output of the C compiler line by line. Source code
+// excerpts printed by the compiler are skipped.
+func relayCCDiagnostics(output string) {
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+
+		if strings.TrimSpace(line) == "" || line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+
+		if m := ccDiagnosticWithLoc.FindStringSubmatch(line); m != nil {
+			report.TaggedReportf(ccDiagnosticKind(m[4]), "cc", "%s:%s:%s: %s", m[1], m[2], m[3], m[5])
+		} else if m := ccDiagnostic.FindStringSubmatch(line); m != nil {
+			report.TaggedReportf(ccDiagnosticKind(m[2]), "cc", "%s: %s", m[1], m[3])
+		} else if strings.Contains(line, "undefined reference") {
+			report.TaggedError("cc", line)
+		} else {
+			report.TaggedNote("cc", line)
+		}
+	}
+}
+
+func ccDiagnosticKind(kind string) report.Kind {
+	switch kind {
+	case "fatal error", "error":
+		return report.KindError
+
+	case "warning":
+		return report.KindWarning
+
+	default:
+		return report.KindNote
+	}
+}
@@ -0,0 +1,41 @@
+package jet
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/saffage/jet/config"
+)
+
+func TestCCompiler(t *testing.T) {
+	exe, cmd, args, runArgs := config.Exe, config.Cmd, config.Args, config.RunArgs
+	t.Cleanup(func() {
+		config.Exe, config.Cmd, config.Args, config.RunArgs = exe, cmd, args, runArgs
+		config.FlagCC = ""
+	})
+
+	tests := []struct {
+		args []string
+		env  string
+		want []string
+	}{
+		{[]string{"build"}, "", []string{"cc"}},
+		{[]string{"run", "main.jet"}, " ", []string{"cc"}},
+		{[]string{"test"}, "gcc", []string{"gcc"}},
+		{[]string{"bench"}, "  ccache gcc -m64 ", []string{"ccache", "gcc", "-m64"}},
+		{[]string{"repl", "-cc", "zig cc"}, "gcc", []string{"zig", "cc"}},
+	}
+
+	for _, test := range tests {
+		t.Setenv("CC", test.env)
+
+		if err := config.ParseArgs(append([]string{"jet"}, test.args...)); err != nil {
+			t.Errorf("%q: %s", test.args, err.Error())
+			continue
+		}
+
+		if cc := cCompiler(); !slices.Equal(cc, test.want) {
+			t.Errorf("%q with CC=%q: expected %q, have %q", test.args, test.env, test.want, cc)
+		}
+	}
+}
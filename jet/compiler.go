@@ -0,0 +1,205 @@
+package jet
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/saffage/jet/ast"
+	"github.com/saffage/jet/cgen"
+	"github.com/saffage/jet/checker"
+	"github.com/saffage/jet/config"
+	"github.com/saffage/jet/token"
+)
+
+// Compiler contains the options shared by the compilation sessions.
+type Compiler struct {
+	// Path to the core library.
+	LibPath string
+
+	// Project the compiled modules belong to. Can be nil.
+	Project *config.Project
+
+	// Trace parser calls (for debugging).
+	TraceParser bool
+
+	// Dump the checker state into '.jet/checker_state.txt'.
+	DumpCheckerState bool
+}
+
+// Session is a single compilation of a program. Each session owns
+// its file table, built-in modules and scopes, so different sessions
+// can be used concurrently.
+type Session struct {
+	// Configuration of the session. Contains the file table.
+	Config *config.Config
+
+	// Main module. Set by [Session.Check].
+	Module *checker.Module
+
+	env *checker.Env
+}
+
+// Creates a new compilation session.
+func (c *Compiler) NewSession() *Session {
+	cfg := config.New()
+	cfg.LibPath = c.LibPath
+	cfg.Project = c.Project
+	cfg.TraceParser = c.TraceParser
+	cfg.DumpCheckerState = c.DumpCheckerState
+	return &Session{Config: cfg}
+}
+
+// Sets the main module of the program. The name of the module is
+// the file name without extension.
+//
+// The file is not read, the content must be specified by the caller.
+func (s *Session) SetMainFile(path string, content []byte) error {
+	path = filepath.Clean(path)
+	fileExt := filepath.Ext(path)
+
+	if fileExt != ".jet" {
+		return fmt.Errorf("expected file extension '.jet', not '%s'", fileExt)
+	}
+
+	name := filepath.Base(path[:len(path)-len(fileExt)])
+	if _, err := token.IsValidIdent(name); err != nil {
+		return errors.Join(fmt.Errorf("invalid module name (file name must be a valid Jet identifier)"), err)
+	}
+
+	s.Config.Files[config.MainFileID] = config.FileInfo{
+		Name: name,
+		Path: path,
+		Buf:  bytes.NewBuffer(content),
+	}
+	return nil
+}
+
+// Checks the main module and all of its imports. The built-in
+// modules are checked on the first call.
+func (s *Session) Check() (*checker.Module, []error) {
+	if s.env == nil {
+		env, errs := checker.NewEnv(s.Config)
+		if len(errs) != 0 {
+			return nil, errs
+		}
+
+		s.env = env
+	}
+
+	m, errs := s.env.CheckFile(config.MainFileID)
+	if len(errs) != 0 {
+		return nil, errs
+	}
+
+	s.Module = m
+	return m, nil
+}
+
+// Checks the expression in the scope of the main module. Returns
+// the type of the expression and its value, if it is constant.
+//
+// Must be called after [Session.Check].
+func (s *Session) CheckExpr(expr ast.Node) (*checker.TypedValue, []error) {
+	if s.Module == nil {
+		panic("the main module is not checked")
+	}
+
+	return s.env.CheckExpr(s.Module, expr)
+}
+
+// Generates C code for the main module and all of its imports into
+// the specified directory. Returns the paths to the generated files.
+//
+// Must be called after [Session.Check].
+func (s *Session) EmitC(dir string) ([]string, []error) {
+	return s.emit(dir, cgen.Generate)
+}
+
+// Same as [Session.EmitC], but the 'main' function of the program
+// runs the specified tests of the main module.
+//
+// Must be called after [Session.Check].
+func (s *Session) EmitTests(dir string, tests []*checker.Func) ([]string, []error) {
+	return s.emit(dir, func(w io.Writer, m *checker.Module) []error {
+		return cgen.GenerateTests(w, m, tests)
+	})
+}
+
+// Same as [Session.EmitC], but the 'main' function of the program
+// runs the specified benchmarks of the main module.
+//
+// Must be called after [Session.Check].
+func (s *Session) EmitBenchmarks(dir string, benchmarks []*checker.Func, benchTime time.Duration) ([]string, []error) {
+	return s.emit(dir, func(w io.Writer, m *checker.Module) []error {
+		return cgen.GenerateBenchmarks(w, m, benchmarks, benchTime)
+	})
+}
+
+// Generates C code for the modules. The main module is generated
+// by the specified function.
+func (s *Session) emit(dir string, generateMain func(io.Writer, *checker.Module) []error) ([]string, []error) {
+	if s.Module == nil {
+		panic("the main module is not checked")
+	}
+
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, []error{err}
+	}
+
+	modules := s.Modules()
+	cFiles := make([]string, 0, len(modules))
+	errs := []error(nil)
+
+	for _, m := range modules {
+		path := filepath.Join(dir, m.Name()+"__jet.c")
+
+		f, err := os.Create(path)
+		if err != nil {
+			return nil, append(errs, err)
+		}
+
+		if m == s.Module {
+			errs = append(errs, generateMain(f, m)...)
+		} else {
+			errs = append(errs, cgen.Generate(f, m)...)
+		}
+
+		f.Close()
+		cFiles = append(cFiles, path)
+	}
+
+	return cFiles, errs
+}
+
+// Returns the main module and all of the modules imported by it
+// directly or indirectly. Each module is listed once, after all
+// of its imports.
+//
+// Must be called after [Session.Check].
+func (s *Session) Modules() []*checker.Module {
+	modules := []*checker.Module{}
+	visited := map[*checker.Module]bool{}
+
+	var visit func(m *checker.Module)
+	visit = func(m *checker.Module) {
+		if visited[m] {
+			return
+		}
+
+		visited[m] = true
+
+		for _, imported := range m.Imports {
+			visit(imported)
+		}
+
+		modules = append(modules, m)
+	}
+
+	visit(s.Module)
+	return modules
+}
@@ -0,0 +1,347 @@
+package jet
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/saffage/jet/checker"
+	"github.com/saffage/jet/internal/report"
+	"github.com/saffage/jet/parser"
+)
+
+func compile(t *testing.T, compiler *Compiler, name, source string) string {
+	session := compiler.NewSession()
+
+	if err := session.SetMainFile(name+".jet", []byte(source)); err != nil {
+		t.Error(err)
+		return ""
+	}
+
+	if _, errs := session.Check(); len(errs) != 0 {
+		t.Errorf("unexpected errors: %v", errs)
+		return ""
+	}
+
+	cFiles, errs := session.EmitC(t.TempDir())
+	if len(errs) != 0 {
+		t.Errorf("unexpected errors: %v", errs)
+		return ""
+	}
+
+	content, err := os.ReadFile(cFiles[len(cFiles)-1])
+	if err != nil {
+		t.Error(err)
+		return ""
+	}
+
+	return string(content)
+}
+
+func TestConcurrentSessions(t *testing.T) {
+	sources := map[string]string{
+		"First":  "var x [3]i32 = [1, 2, 3]\n\nfunc main() {\n\t@print(x[0])\n}\n",
+		"Second": "func twice(x i32) i32 {\n\tx * 2\n}\n\nfunc main() {\n\t@print(twice(21))\n}\n",
+	}
+	compiler := &Compiler{LibPath: filepath.Join("..", "lib")}
+	expected := map[string]string{}
+
+	for name, source := range sources {
+		expected[name] = compile(t, compiler, name, source)
+	}
+
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < 4; i++ {
+		for name, source := range sources {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				if actual := compile(t, compiler, name, source); actual != expected[name] {
+					t.Errorf("generated code of module '%s' differs:\n%s", name, actual)
+				}
+			}()
+		}
+	}
+
+	wg.Wait()
+}
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func checkMain(t *testing.T, dir string) (*Session, []error) {
+	path := filepath.Join(dir, "Main.jet")
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session := (&Compiler{LibPath: filepath.Join("..", "lib")}).NewSession()
+
+	if err := session.SetMainFile(path, content); err != nil {
+		t.Fatal(err)
+	}
+
+	_, errs := session.Check()
+	return session, errs
+}
+
+func TestSharedImport(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"Main.jet": "import B\nimport C\n\nfunc main() {\n\t@print(B.b() + C.c())\n}\n",
+		"B.jet":    "import D\n\nfunc b() i32 {\n\tD.d()\n}\n",
+		"C.jet":    "import D\n\nfunc c() i32 {\n\tD.d()\n}\n",
+		"D.jet":    "func d() i32 {\n\t1\n}\n",
+	})
+
+	session, errs := checkMain(t, dir)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	b, c := session.Module.Imports[0], session.Module.Imports[1]
+	if b.Imports[0] != c.Imports[0] {
+		t.Errorf("module 'D' was checked twice")
+	}
+
+	if n := len(session.Modules()); n != 4 {
+		t.Errorf("unexpected number of modules; want 4, have %d", n)
+	}
+}
+
+func TestImportCycle(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"Main.jet": "import B\n\nfunc main() {}\n",
+		"B.jet":    "import C\n",
+		"C.jet":    "import Main\n",
+	})
+
+	_, errs := checkMain(t, dir)
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, have %d: %v", len(errs), errs)
+	}
+
+	err, ok := errs[0].(*checker.Error)
+	if !ok {
+		t.Fatalf("unexpected error type %T", errs[0])
+	}
+
+	if want := "import cycle not allowed: Main -> B -> C -> Main"; err.Message != want {
+		t.Errorf("unexpected message; want %q, have %q", want, err.Message)
+	}
+
+	if len(err.Notes) != 2 {
+		t.Errorf("expected 2 notes, have %d", len(err.Notes))
+	}
+}
+
+func TestErrorsOrder(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"Main.jet": "import C\nimport B\n\nfunc main() {\n\tx\n}\n",
+		"B.jet":    "func b() {\n\ty\n}\n",
+		"C.jet":    "func c() {\n\tz\n}\n",
+	})
+
+	for i := 0; i < 10; i++ {
+		session, errs := checkMain(t, dir)
+		if len(errs) != 3 {
+			t.Fatalf("expected 3 errors, have %d: %v", len(errs), errs)
+		}
+
+		for j, want := range []string{"B.jet", "C.jet", "Main.jet"} {
+			fileID := errs[j].(*checker.Error).Node.Pos().FileID
+
+			if have := filepath.Base(session.Config.Files[fileID].Path); have != want {
+				t.Fatalf("unexpected file of the error %d; want %s, have %s", j, want, have)
+			}
+		}
+	}
+}
+
+func TestSyntaxErrorRecovery(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"Main.jet": "const C = )\n\nfunc main() {\n\tx\n}\n",
+	})
+
+	_, errs := checkMain(t, dir)
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, have %d: %v", len(errs), errs)
+	}
+
+	if _, ok := errs[0].(parser.Error); !ok {
+		t.Errorf("expected a syntax error, have %v", errs[0])
+	}
+
+	if _, ok := errs[1].(*checker.Error); !ok {
+		t.Errorf("expected a checker error, have %v", errs[1])
+	}
+}
+
+// Each explanation must contain an example that is reported with its
+// code. The corrected example, if any, must have no errors.
+func TestExplanationExamples(t *testing.T) {
+	for _, code := range report.Codes() {
+		t.Run(string(code), func(t *testing.T) {
+			text, _ := report.Explain(code)
+			examples := codeBlocks(text)
+
+			if len(examples) == 0 {
+				t.Fatal("explanation has no examples")
+			}
+
+			_, errs := checkMain(t, writeFiles(t, map[string]string{"Main.jet": examples[0]}))
+			codes := []report.Code{}
+
+			for _, err := range errs {
+				codes = append(codes, report.ToDiagnostic(err).Code)
+			}
+
+			if !slices.Contains(codes, code) {
+				t.Errorf("expected the example to be reported with the code, have %v: %v", codes, errs)
+			}
+
+			if len(examples) > 1 {
+				_, errs := checkMain(t, writeFiles(t, map[string]string{"Main.jet": examples[1]}))
+				if len(errs) != 0 {
+					t.Errorf("unexpected errors in the corrected example: %v", errs)
+				}
+			}
+		})
+	}
+}
+
+// Returns the contents of the fenced code blocks.
+func codeBlocks(text string) []string {
+	blocks := []string{}
+
+	for {
+		_, rest, ok := strings.Cut(text, "```jet\n")
+		if !ok {
+			return blocks
+		}
+
+		block, rest, _ := strings.Cut(rest, "```")
+		blocks = append(blocks, block)
+		text = rest
+	}
+}
+
+func TestEmitTests(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"Main.jet": "@(Test)\nfunc testA() {\n\t@assert(1 == 1)\n}\n\n" +
+			"@(Test)\nfunc testB() {}\n\nfunc main() {}\n",
+	})
+
+	session, errs := checkMain(t, dir)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	tests, ok := selectFuncs(session.Module.Tests, "B$")
+	if !ok || len(tests) != 1 || tests[0].Name() != "testB" {
+		t.Fatalf("unexpected selected tests: %v", tests)
+	}
+
+	cFiles, errs := session.EmitTests(t.TempDir(), session.Module.Tests)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	content, err := os.ReadFile(cFiles[len(cFiles)-1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{
+		"void Main__main(void)",
+		`{"testA", "Main.jet:2:6", Main__testA},`,
+		"return __jet_run_tests(funcs, 2);",
+	} {
+		if !strings.Contains(string(content), s) {
+			t.Errorf("expected %q in the generated code:\n%s", s, content)
+		}
+	}
+}
+
+func TestEmitBenchmarks(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"Main.jet": "@(Bench)\nfunc benchSum() {\n\tvar x = 1 + 2\n}\n\nfunc main() {}\n",
+	})
+
+	session, errs := checkMain(t, dir)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	cFiles, errs := session.EmitBenchmarks(t.TempDir(), session.Module.Benchmarks, 2*time.Second)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	content, err := os.ReadFile(cFiles[len(cFiles)-1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{
+		"void Main__main(void)",
+		`{"benchSum", "Main.jet:2:6", Main__benchSum},`,
+		"return __jet_run_benchmarks(funcs, 1, 2000000000u);",
+	} {
+		if !strings.Contains(string(content), s) {
+			t.Errorf("expected %q in the generated code:\n%s", s, content)
+		}
+	}
+}
+
+func TestREPL(t *testing.T) {
+	input := strings.Join([]string{
+		"1 + 2 * 3",
+		"const N = 10",
+		"func twice(x i32) i32 {",
+		"\tx * 2",
+		"}",
+		"N * 4",
+		":type twice",
+		"const N = 2.5",
+		":type N",
+		"const M = N",
+		":decls",
+		":quit",
+		"N",
+	}, "\n")
+	output := strings.Builder{}
+	compiler := &Compiler{LibPath: filepath.Join("..", "lib")}
+
+	if code := runREPL(compiler, strings.NewReader(input), &output); code != 0 {
+		t.Fatalf("unexpected exit code %d", code)
+	}
+
+	expected := "Jet REPL, type ':help' for help.\n" +
+		"> 7 (untyped int)\n" +
+		"> > ... ... > 40 (untyped int)\n" +
+		"> func(i32) i32\n" +
+		"> > f64\n" +
+		"> > func twice(x i32) i32 {\n\tx * 2\n}\nconst N = 2.5\nconst M = N\n" +
+		"> "
+
+	if output.String() != expected {
+		t.Errorf("unexpected output:\n%s", output.String())
+	}
+}
@@ -0,0 +1,40 @@
+package jet
+
+import (
+	"io"
+	"os"
+
+	"github.com/saffage/jet/checker"
+	"github.com/saffage/jet/config"
+	"github.com/saffage/jet/doc"
+	"github.com/saffage/jet/internal/report"
+)
+
+// Writes the documentation of the module to the file specified by
+// the '-o' flag or to the standard output.
+func writeDoc(m *checker.Module) int {
+	w := io.Writer(os.Stdout)
+
+	if config.FlagOutput != "" {
+		f, err := os.Create(config.FlagOutput)
+		if err != nil {
+			report.Errorf("cannot create output file: %s", err.Error())
+			return 1
+		}
+		defer f.Close()
+
+		w = f
+	}
+
+	render := doc.New(m).Markdown
+	if config.FlagHTML {
+		render = doc.New(m).HTML
+	}
+
+	if err := render(w); err != nil {
+		report.Errorf("while writing documentation: %s", err.Error())
+		return 1
+	}
+
+	return 0
+}
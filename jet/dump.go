@@ -0,0 +1,89 @@
+package jet
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/saffage/jet/ast"
+	"github.com/saffage/jet/config"
+	"github.com/saffage/jet/internal/report"
+	"github.com/saffage/jet/parser"
+	"github.com/saffage/jet/scanner"
+)
+
+// Displays the tokens of the specified file or encodes them in JSON
+// format if the '-json' flag is set. Whitespace tokens are skipped.
+func printTokens(cfg *config.Config, fileID config.FileID) int {
+	tokens, errs := scanner.Scan(cfg.Files[fileID].Buf.Bytes(), fileID, scanner.SkipWhitespace)
+	if len(errs) != 0 {
+		report.Errors(errs...)
+		return 1
+	}
+
+	if config.FlagJSON {
+		encoded, err := json.MarshalIndent(tokens, "", "    ")
+		if err != nil {
+			panic(err)
+		}
+
+		fmt.Println(string(encoded))
+		return 0
+	}
+
+	for _, tok := range tokens {
+		fmt.Println(tok.String())
+	}
+
+	return 0
+}
+
+// Displays the AST of the specified file as the recreated source
+// code or in JSON format if the '-json' flag is set. The schema is
+// described in [ast.MarshalNode].
+func printAST(cfg *config.Config, fileID config.FileID) int {
+	// Comments are kept, the parser attaches doc comments to the declarations.
+	tokens, errs := scanner.Scan(cfg.Files[fileID].Buf.Bytes(), fileID, scanner.SkipWhitespace)
+	if len(errs) != 0 {
+		report.Errors(errs...)
+		return 1
+	}
+
+	parserFlags := parser.DefaultFlags
+	if cfg.TraceParser {
+		parserFlags |= parser.Trace
+	}
+
+	nodeList, errs := parser.Parse(cfg, tokens, parserFlags)
+	if len(errs) != 0 {
+		report.Errors(errs...)
+		return 1
+	}
+
+	if nodeList == nil {
+		return 0
+	}
+
+	if config.FlagJSON {
+		encoded, err := ast.MarshalNode(nodeList)
+		if err != nil {
+			panic(err)
+		}
+
+		buf := bytes.Buffer{}
+		if err := json.Indent(&buf, encoded, "", "    "); err != nil {
+			panic(err)
+		}
+
+		fmt.Println(buf.String())
+		return 0
+	}
+
+	for i, node := range nodeList.Nodes {
+		if _, isEmpty := node.(*ast.Empty); i < len(nodeList.Nodes)-1 || !isEmpty {
+			fmt.Println(node.String())
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,148 @@
+package jet
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/saffage/jet/config"
+	"github.com/saffage/jet/format"
+	"github.com/saffage/jet/internal/diff"
+	"github.com/saffage/jet/internal/report"
+)
+
+// Formats the specified files and the '.jet' files in the specified
+// directories. Depending on the flags, the files are rewritten, listed
+// ('-check') or their changes are displayed ('-diff').
+func formatFiles(paths []string) int {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	cfg := config.New()
+	useConfig(cfg)
+
+	files, ok := collectSourceFiles(paths)
+	exitCode := 0
+
+	if !ok {
+		exitCode = 1
+	}
+
+	for _, path := range files {
+		changed, ok := formatFile(cfg, path)
+
+		switch {
+		case !ok:
+			exitCode = 1
+
+		case changed && (config.FlagCheck || config.FlagDiff):
+			exitCode = 1
+		}
+	}
+
+	return exitCode
+}
+
+// Formats the file. Returns whether the formatting of the file differs.
+func formatFile(cfg *config.Config, path string) (changed, ok bool) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		report.Errorf(err.Error())
+		return false, false
+	}
+
+	src, err := os.ReadFile(path)
+	if err != nil {
+		report.Errorf("while reading file '%s': %s", path, err.Error())
+		return false, false
+	}
+
+	fileID := cfg.NextFileID()
+	cfg.Files[fileID] = config.FileInfo{
+		Name: strings.TrimSuffix(filepath.Base(path), ".jet"),
+		Path: path,
+		Buf:  bytes.NewBuffer(src),
+	}
+
+	formatted, errs := format.Source(fileID, src)
+	if len(errs) > 0 {
+		report.Errors(errs...)
+		return false, false
+	}
+
+	if bytes.Equal(src, formatted) {
+		return false, true
+	}
+
+	switch {
+	case config.FlagDiff:
+		slashPath := filepath.ToSlash(path)
+		fmt.Print(diff.Unified("a/"+slashPath, "b/"+slashPath, src, formatted))
+
+	case config.FlagCheck:
+		fmt.Println(path)
+
+	default:
+		if err := os.WriteFile(path, formatted, stat.Mode().Perm()); err != nil {
+			report.Errorf("while writing file '%s': %s", path, err.Error())
+			return true, false
+		}
+
+		report.Debugf("formatted '%s'", path)
+	}
+
+	return true, true
+}
+
+// Returns the specified files and the '.jet' files found in the
+// specified directories. Hidden directories (e.g. '.jet') are skipped.
+func collectSourceFiles(paths []string) ([]string, bool) {
+	files := []string{}
+	ok := true
+
+	for _, path := range paths {
+		path = filepath.Clean(path)
+		stat, err := os.Stat(path)
+		if err != nil {
+			report.Errorf(err.Error())
+			ok = false
+			continue
+		}
+
+		if !stat.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		root := path
+		err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if entry.IsDir() {
+				if path != root && strings.HasPrefix(entry.Name(), ".") {
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+
+			if filepath.Ext(path) == ".jet" {
+				files = append(files, path)
+			}
+
+			return nil
+		})
+		if err != nil {
+			report.Errorf(err.Error())
+			ok = false
+		}
+	}
+
+	return files, ok
+}
@@ -0,0 +1,76 @@
+package jet
+
+import (
+	"path/filepath"
+
+	"github.com/saffage/jet/checker"
+	"github.com/saffage/jet/config"
+	"github.com/saffage/jet/internal/report"
+)
+
+func process(session *Session) int {
+	cfg := session.Config
+
+	m, errs := session.Check()
+	if len(errs) != 0 {
+		report.Errors(errs...)
+		return 1
+	}
+
+	switch config.Cmd.Name {
+	case "doc":
+		return writeDoc(m)
+
+	case "test":
+		return runTests(session)
+
+	case "bench":
+		return runBenchmarks(session)
+	}
+
+	if config.Cmd.Name != "check" {
+		dir := outputDir(cfg, config.MainFileID)
+		report.Hintf("emit C...")
+
+		cFiles, errs := session.EmitC(dir)
+		if len(errs) != 0 {
+			report.Errors(errs...)
+			return 1
+		}
+
+		switch config.Cmd.Name {
+		case "run":
+			return buildAndRun(cfg, cFiles, exeName(cfg, m))
+
+		case "build":
+			output := config.FlagOutput
+			if output == "" {
+				output = filepath.Join(dir, exeName(cfg, m))
+			}
+
+			if !build(cfg, cFiles, output) {
+				return 1
+			}
+		}
+	}
+
+	return 0
+}
+
+// Returns the directory of the generated C code and the executable.
+func outputDir(cfg *config.Config, fileID config.FileID) string {
+	if cfg.Project != nil {
+		return cfg.Project.OutputDir
+	}
+
+	return filepath.Join(filepath.Dir(cfg.Files[fileID].Path), ".jet")
+}
+
+// Returns the file name of the executable.
+func exeName(cfg *config.Config, m *checker.Module) string {
+	if cfg.Project != nil {
+		return cfg.Project.Name + exeSuffix()
+	}
+
+	return m.Name() + exeSuffix()
+}
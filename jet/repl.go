@@ -0,0 +1,416 @@
+package jet
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/saffage/jet/ast"
+	"github.com/saffage/jet/config"
+	"github.com/saffage/jet/internal/report"
+	"github.com/saffage/jet/parser"
+	"github.com/saffage/jet/scanner"
+	"github.com/saffage/jet/token"
+	"github.com/saffage/jet/types"
+)
+
+const replHelp = `Enter a declaration, an expression or a statement.
+
+Declarations are kept in the session and can be used by the subsequent
+inputs, a new declaration replaces the previous one with the same name.
+Constant expressions are evaluated by the compiler, other code is
+compiled and run as the body of the 'main' function.
+
+Commands:
+  :type <expr>  display the type of the expression
+  :decls        display the declarations of the session
+  :reset        remove all declarations
+  :help         display this help
+  :quit         exit the REPL
+`
+
+// Source file of the REPL session. Imports are resolved relative
+// to the current directory.
+const replFile = "repl.jet"
+
+// REPL reads the user input and evaluates it in the session that
+// contains all of the declarations entered before.
+type repl struct {
+	compiler *Compiler
+	in       *bufio.Reader
+	out      io.Writer
+
+	// Declarations of the session in the input order.
+	decls []replDecl
+
+	// Session with the checked declarations. Nil if the declarations
+	// were changed since the last check.
+	session *Session
+}
+
+type replDecl struct {
+	// Names defined by the declaration.
+	names []string
+	src   string
+}
+
+// Runs the interactive REPL until the end of the input or the
+// ':quit' command.
+func runREPL(compiler *Compiler, in io.Reader, out io.Writer) int {
+	r := &repl{
+		compiler: compiler,
+		in:       bufio.NewReader(in),
+		out:      out,
+	}
+
+	if _, ok := r.check(); !ok {
+		return 1
+	}
+
+	fmt.Fprintln(out, "Jet REPL, type ':help' for help.")
+
+	for {
+		input, ok := r.read()
+		if !ok {
+			return 0
+		}
+
+		if !r.eval(input) {
+			return 0
+		}
+	}
+}
+
+// Reads the input until all of the brackets are closed. Returns
+// false at the end of the input.
+func (r *repl) read() (string, bool) {
+	buf := strings.Builder{}
+	fmt.Fprint(r.out, "> ")
+
+	for {
+		line, err := r.in.ReadString('\n')
+		buf.WriteString(line)
+
+		if err != nil {
+			if buf.Len() == 0 {
+				fmt.Fprintln(r.out)
+				return "", false
+			}
+
+			return buf.String(), true
+		}
+
+		if isInputComplete(buf.String()) {
+			return buf.String(), true
+		}
+
+		fmt.Fprint(r.out, "... ")
+	}
+}
+
+// Reports whether all of the brackets in the input are closed.
+func isInputComplete(input string) bool {
+	tokens, _ := scanner.Scan([]byte(input), 0, scanner.SkipWhitespace|scanner.SkipComments)
+	depth := 0
+
+	for _, tok := range tokens {
+		switch tok.Kind {
+		case token.LParen, token.LCurly, token.LBracket:
+			depth++
+
+		case token.RParen, token.RCurly, token.RBracket:
+			depth--
+		}
+	}
+
+	return depth <= 0
+}
+
+// Evaluates the input. Returns false if the REPL must exit.
+func (r *repl) eval(input string) bool {
+	trimmed := strings.TrimSpace(input)
+
+	if strings.HasPrefix(trimmed, ":") {
+		return r.command(trimmed)
+	}
+
+	if trimmed == "" {
+		return true
+	}
+
+	session, ok := r.check()
+	if !ok {
+		return true
+	}
+
+	fileID := r.addInput(session, input)
+
+	tokens, errs := scanner.Scan([]byte(input), fileID, scanner.SkipWhitespace)
+	if len(errs) != 0 {
+		report.Errors(errs...)
+		return true
+	}
+
+	list, errs := parser.Parse(session.Config, tokens, parser.DefaultFlags)
+	if len(errs) != 0 {
+		report.Errors(errs...)
+		return true
+	}
+
+	nodes := []ast.Node{}
+	numDecls := 0
+
+	for _, node := range list.Nodes {
+		switch node.(type) {
+		case *ast.Empty:
+			continue
+
+		case ast.Decl, *ast.Import:
+			numDecls++
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	switch {
+	case len(nodes) == 0:
+
+	case numDecls == len(nodes):
+		r.declare(nodes, input)
+
+	case numDecls != 0:
+		report.Errorf("declarations and statements cannot be mixed in one input")
+
+	case len(nodes) == 1:
+		r.evalExpr(session, fileID, input)
+
+	default:
+		r.exec(input)
+	}
+
+	return true
+}
+
+func (r *repl) command(input string) bool {
+	name, arg, _ := strings.Cut(input, " ")
+
+	switch name {
+	case ":quit", ":q":
+		return false
+
+	case ":help":
+		fmt.Fprint(r.out, replHelp)
+
+	case ":decls":
+		for _, decl := range r.decls {
+			fmt.Fprintln(r.out, strings.TrimSpace(decl.src))
+		}
+
+	case ":reset":
+		r.decls = nil
+		r.session = nil
+
+	case ":type":
+		if strings.TrimSpace(arg) == "" {
+			report.Errorf("expected expression after ':type'")
+			break
+		}
+
+		if session, ok := r.check(); ok {
+			input := "@type_of(" + arg + ")"
+			r.evalExpr(session, r.addInput(session, input), input)
+		}
+
+	default:
+		report.Errorf("unknown command '%s', type ':help' for help", name)
+	}
+
+	return true
+}
+
+// Adds the declarations to the session. The previous declarations
+// with the same names are replaced. If the declarations contain
+// errors, the session is not changed.
+func (r *repl) declare(nodes []ast.Node, input string) {
+	names := []string{}
+
+	for _, node := range nodes {
+		if decl, _ := node.(ast.Decl); decl != nil {
+			if _, isFunc := decl.(*ast.FuncDecl); isFunc && decl.Ident().Name == "main" {
+				report.Errorf("function 'main' cannot be declared in the REPL")
+				return
+			}
+
+			names = append(names, decl.Ident().Name)
+		}
+	}
+
+	decls := make([]replDecl, 0, len(r.decls)+1)
+
+	for _, decl := range r.decls {
+		if !slices.ContainsFunc(decl.names, func(name string) bool { return slices.Contains(names, name) }) {
+			decls = append(decls, decl)
+		}
+	}
+
+	decls = append(decls, replDecl{names, input})
+
+	session, errs := r.newSession(declsSource(decls))
+	if len(errs) != 0 {
+		report.Errors(errs...)
+		return
+	}
+
+	r.decls = decls
+	r.session = session
+}
+
+// Evaluates the expression. The value of a constant expression is
+// displayed without running the code.
+func (r *repl) evalExpr(session *Session, fileID config.FileID, input string) {
+	expr, errs := parser.ParseExpr(session.Config, fileID, []byte(input))
+	if len(errs) != 0 {
+		// Not an expression, e.g. a loop.
+		r.exec(input)
+		return
+	}
+
+	value, errs := session.CheckExpr(expr)
+	if len(errs) != 0 {
+		report.Errors(errs...)
+		return
+	}
+
+	switch {
+	case value.Value != nil:
+		fmt.Fprintf(r.out, "%s (%s)\n", value.Value, value.Type)
+
+	case types.IsTypeDesc(value.Type):
+		fmt.Fprintln(r.out, types.SkipTypeDesc(value.Type))
+
+	case isPrintable(value.Type):
+		if r.exec(fmt.Sprintf("@print(%s)", expr)) {
+			fmt.Fprintln(r.out)
+		}
+
+	default:
+		r.exec(input)
+	}
+}
+
+// Compiles and runs the code as the body of the 'main' function.
+// Reports whether the program was built and exited successfully.
+func (r *repl) exec(body string) bool {
+	src := declsSource(r.decls) + "\nfunc main() {\n" + body + "\n}\n"
+
+	session, errs := r.newSession(src)
+	if len(errs) != 0 {
+		report.Errors(errs...)
+		return false
+	}
+
+	dir, err := os.MkdirTemp("", "jet-repl-")
+	if err != nil {
+		report.Errorf("cannot create temporary directory: %s", err.Error())
+		return false
+	}
+	defer os.RemoveAll(dir)
+
+	cFiles, errs := session.EmitC(dir)
+	if len(errs) != 0 {
+		report.Errors(errs...)
+		return false
+	}
+
+	return buildAndRun(session.Config, cFiles, "repl"+exeSuffix()) == 0
+}
+
+// Returns the session with the checked declarations.
+func (r *repl) check() (*Session, bool) {
+	if r.session == nil {
+		session, errs := r.newSession(declsSource(r.decls))
+		if len(errs) != 0 {
+			report.Errors(errs...)
+			return nil, false
+		}
+
+		r.session = session
+	}
+
+	useConfig(r.session.Config)
+	return r.session, true
+}
+
+// Creates a new session and checks the source as the main module.
+func (r *repl) newSession(src string) (*Session, []error) {
+	session := r.compiler.NewSession()
+	useConfig(session.Config)
+
+	if err := session.SetMainFile(replFile, []byte(src)); err != nil {
+		return nil, []error{err}
+	}
+
+	if _, errs := session.Check(); len(errs) != 0 {
+		return nil, errs
+	}
+
+	return session, nil
+}
+
+// Adds the input to the file table of the session, so the reports
+// can display it.
+func (r *repl) addInput(session *Session, input string) config.FileID {
+	fileID := session.Config.NextFileID()
+	session.Config.Files[fileID] = config.FileInfo{
+		Name: "input",
+		Path: "<input>",
+		Buf:  bytes.NewBufferString(input),
+	}
+
+	return fileID
+}
+
+func declsSource(decls []replDecl) string {
+	buf := strings.Builder{}
+
+	for _, decl := range decls {
+		buf.WriteString(decl.src)
+
+		if !strings.HasSuffix(decl.src, "\n") {
+			buf.WriteByte('\n')
+		}
+	}
+
+	return buf.String()
+}
+
+// Reports whether the value of the type can be printed with '@print'.
+func isPrintable(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+
+	p := types.AsPrimitive(types.SkipAlias(t))
+	if p == nil {
+		return false
+	}
+
+	switch p.Kind() {
+	case types.KindI8,
+		types.KindI16,
+		types.KindI32,
+		types.KindI64,
+		types.KindU8,
+		types.KindU16,
+		types.KindU32,
+		types.KindU64:
+		return true
+
+	default:
+		return false
+	}
+}
@@ -0,0 +1,53 @@
+package jet
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/saffage/jet/config"
+	"github.com/saffage/jet/internal/report"
+)
+
+// Builds the executable into a temporary directory and runs it
+// with the arguments specified after the file name. Returns the
+// exit code of the program.
+func buildAndRun(cfg *config.Config, cFiles []string, exeName string) int {
+	dir, err := os.MkdirTemp("", "jet-run-")
+	if err != nil {
+		report.Errorf("cannot create temporary directory: %s", err.Error())
+		return 1
+	}
+	defer os.RemoveAll(dir)
+
+	exe := filepath.Join(dir, exeName)
+
+	if !build(cfg, cFiles, exe) {
+		return 1
+	}
+
+	cmd := exec.Command(exe, config.RunArgs...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		exitErr := (*exec.ExitError)(nil)
+
+		if !errors.As(err, &exitErr) {
+			report.Errorf("cannot run '%s': %s", exe, err.Error())
+			return 1
+		}
+
+		if code := exitErr.ExitCode(); code >= 0 {
+			return code
+		}
+
+		// Terminated by a signal.
+		report.Errorf("program terminated: %s", exitErr.Error())
+		return 1
+	}
+
+	return 0
+}
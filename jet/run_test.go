@@ -0,0 +1,241 @@
+package jet
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Compiles the program with the C compiler and runs it. Returns the
+// output of the program without the trailing NUL characters written
+// by '@print'. The test is skipped if there is no C compiler.
+func run(t *testing.T, source string) string {
+	t.Helper()
+
+	if _, err := exec.LookPath(cCompiler()[0]); err != nil {
+		t.Skip("C compiler is not found")
+	}
+
+	session := (&Compiler{LibPath: filepath.Join("..", "lib")}).NewSession()
+
+	if err := session.SetMainFile("Main.jet", []byte(source)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, errs := session.Check(); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	dir := t.TempDir()
+
+	cFiles, errs := session.EmitC(dir)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	exe := filepath.Join(dir, "main"+exeSuffix())
+	if !build(session.Config, cFiles, exe) {
+		t.Fatal("cannot build the program")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, exe).Output()
+	if ctx.Err() != nil {
+		t.Fatal("program timed out")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return strings.ReplaceAll(string(output), "\x00", "")
+}
+
+func TestRunReturn(t *testing.T) {
+	const source = `func fib(n i32) i32 {
+	if n < 2 {
+		return n
+	}
+
+	fib(n - 1) + fib(n - 2)
+}
+
+func main() {
+	@print(fib(10))
+
+	if true {
+		return
+	}
+
+	@print(0)
+}
+`
+	if output := run(t, source); output != "55" {
+		t.Errorf("unexpected output %q, expected %q", output, "55")
+	}
+}
+
+func TestRunLabeledLoops(t *testing.T) {
+	const source = `func main() {
+	var i = 0
+	var n = 0
+
+	rows: while i < 3 {
+		i += 1
+
+		while true {
+			if i == 1 { continue rows }
+			n += 10
+			break rows
+		}
+	}
+
+	cols: while false {}
+
+	@print(i)
+	@print(n)
+}
+`
+	if output := run(t, source); output != "210" {
+		t.Errorf("unexpected output %q, expected %q", output, "210")
+	}
+}
+
+func TestRunForLoops(t *testing.T) {
+	const source = `func main() {
+	var arr [3]i32 = [1, 2, 3]
+	var n u8 = 4
+	var sum = 0
+
+	for i in 1..n {
+		sum += 1
+	}
+	for i, x in arr {
+		sum += x * 10 + i * 1000
+	}
+	for _ in 0..<2 {
+		sum += 100
+	}
+
+	@print(sum)
+}
+`
+	if output := run(t, source); output != "3264" {
+		t.Errorf("unexpected output %q, expected %q", output, "3264")
+	}
+}
+
+func TestRunMatch(t *testing.T) {
+	const source = `enum Color {
+	Red
+	Green
+}
+
+func code(c Color) i32 {
+	match c {
+		Color.Red => 1
+		Color.Green => return 2
+	}
+}
+
+func main() {
+	var i = 0
+
+	while true {
+		match i {
+			0, 1 => i += 1
+			_ => break
+		}
+	}
+
+	@print(code(Color.Red))
+	@print(code(Color.Green))
+	@print(i)
+}
+`
+	if output := run(t, source); output != "122" {
+		t.Errorf("unexpected output %q, expected %q", output, "122")
+	}
+}
+
+// The inclusive range may end with the maximum value of the type.
+func TestRunInclusiveRange(t *testing.T) {
+	const source = `func main() {
+	var s u8 = 250
+	var e u8 = 255
+	var n = 0
+
+	for i in s..e {
+		n += 1
+	}
+	for i in 3..3 {
+		if true { continue }
+	}
+	for i in e..s {
+		n += 100
+	}
+
+	@print(n)
+}
+`
+	if output := run(t, source); output != "6" {
+		t.Errorf("unexpected output %q, expected %q", output, "6")
+	}
+}
+
+func TestRunMatchValue(t *testing.T) {
+	const source = `enum Color {
+	Red
+	Green
+	Blue
+}
+
+var G = match Color.Green {
+	Color.Red => 1
+	_ => 2
+}
+
+func code(c Color) i32 {
+	return match c {
+		Color.Red => 10
+		Color.Green => {
+			var x = 5
+			x * 4
+		}
+		Color.Blue => 30
+	}
+}
+
+func main() {
+	var c = Color.Blue
+	var v = match c {
+		Color.Blue => match G {
+			2 => 3
+			_ => 4
+		}
+		_ => 1
+	}
+	var n = 0
+
+	while true {
+		var next = match n {
+			5 => break
+			_ => n + 1
+		}
+		n = next
+	}
+
+	@print(v)
+	@print(code(Color.Green))
+	@print(n)
+	@print(G)
+}
+`
+	if output := run(t, source); output != "32052" {
+		t.Errorf("unexpected output %q, expected %q", output, "32052")
+	}
+}
@@ -0,0 +1,56 @@
+package jet
+
+import (
+	"regexp"
+
+	"github.com/saffage/jet/checker"
+	"github.com/saffage/jet/config"
+	"github.com/saffage/jet/internal/report"
+)
+
+// Builds and runs the tests of the main module selected by the
+// '-run' flag. Returns the exit code of the test executable.
+func runTests(session *Session) int {
+	tests, ok := selectFuncs(session.Module.Tests, config.FlagRun)
+	if !ok {
+		return 1
+	}
+
+	if len(tests) == 0 {
+		report.Warningf("no tests to run")
+		return 0
+	}
+
+	cfg := session.Config
+	cFiles, errs := session.EmitTests(outputDir(cfg, config.MainFileID), tests)
+	if len(errs) != 0 {
+		report.Errors(errs...)
+		return 1
+	}
+
+	return buildAndRun(cfg, cFiles, session.Module.Name()+"_test"+exeSuffix())
+}
+
+// Returns the functions whose names match the pattern. Empty pattern
+// matches all functions.
+func selectFuncs(funcs []*checker.Func, pattern string) ([]*checker.Func, bool) {
+	if pattern == "" {
+		return funcs, true
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		report.Errorf("invalid '-run' pattern: %s", err.Error())
+		return nil, false
+	}
+
+	selected := []*checker.Func{}
+
+	for _, fn := range funcs {
+		if re.MatchString(fn.Name()) {
+			selected = append(selected, fn)
+		}
+	}
+
+	return selected, true
+}
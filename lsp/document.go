@@ -0,0 +1,567 @@
+package lsp
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/saffage/jet/ast"
+	"github.com/saffage/jet/checker"
+	"github.com/saffage/jet/config"
+	"github.com/saffage/jet/internal/report"
+	"github.com/saffage/jet/token"
+	"github.com/saffage/jet/types"
+)
+
+// Document is an open text document and the result of its checking.
+type document struct {
+	uri     string
+	path    string
+	version int
+
+	// Character offsets of the positions are counted in bytes instead
+	// of UTF-16 code units (see [positionEncodingUTF8]).
+	utf8 bool
+
+	cfg         *config.Config
+	module      *checker.Module   // Nil if the document can't be checked.
+	modules     []*checker.Module // The module and all of its imports.
+	diagnostics []Diagnostic
+
+	// Quick fixes of the diagnostics.
+	actions []CodeAction
+
+	// Lines of the files by ID, used to convert the positions.
+	lines map[config.FileID][][]byte
+}
+
+// Checks the document and all of its imports. The errors are stored
+// as the diagnostics of the document.
+func (doc *document) check(libPath string, content []byte) {
+	errs := []error(nil)
+
+	defer func() {
+		if err := recover(); err != nil {
+			errs = append(errs, fmt.Errorf("internal error: %v", err))
+		}
+
+		doc.diagnostics = doc.convertErrors(errs)
+	}()
+
+	doc.cfg = config.New()
+	name := strings.TrimSuffix(filepath.Base(doc.path), filepath.Ext(doc.path))
+
+	if _, err := token.IsValidIdent(name); err != nil {
+		errs = []error{fmt.Errorf("invalid module name (file name must be a valid Jet identifier): %s", err.Error())}
+		return
+	}
+
+	if projectPath := config.FindProject(filepath.Dir(doc.path)); projectPath != "" {
+		project, err := config.LoadProject(projectPath)
+		if err != nil {
+			errs = []error{err}
+			return
+		}
+
+		doc.cfg.Project = project
+	}
+
+	doc.cfg.LibPath = libPath
+	if libPath == "" {
+		doc.cfg.LibPath = config.CoreLibPath(doc.cfg.Project)
+	}
+
+	doc.cfg.Files[config.MainFileID] = config.FileInfo{
+		Name: name,
+		Path: doc.path,
+		Buf:  bytes.NewBuffer(content),
+	}
+
+	env, errs := checker.NewEnv(doc.cfg)
+	if len(errs) != 0 {
+		return
+	}
+
+	doc.module, errs = env.CheckFile(config.MainFileID)
+	doc.modules = modules(doc.module)
+}
+
+// Returns the module and all of the modules imported by it
+// directly or indirectly.
+func modules(m *checker.Module) []*checker.Module {
+	result := []*checker.Module{}
+	visited := map[*checker.Module]bool{}
+
+	var visit func(m *checker.Module)
+	visit = func(m *checker.Module) {
+		if m == nil || visited[m] {
+			return
+		}
+
+		visited[m] = true
+		result = append(result, m)
+
+		for _, imported := range m.Imports {
+			visit(imported)
+		}
+	}
+
+	visit(m)
+	return result
+}
+
+// Converts the errors to the diagnostics of the document. Errors
+// located in other files are displayed at the start of the document.
+func (doc *document) convertErrors(errs []error) []Diagnostic {
+	diagnostics := make([]Diagnostic, 0, len(errs))
+
+	for _, err := range errs {
+		d := report.ToDiagnostic(err)
+		diagnostic := Diagnostic{
+			Severity: SeverityError,
+			Code:     string(d.Code),
+			Source:   "jet",
+			Message:  d.Message,
+		}
+
+		if d.Tag != "" {
+			diagnostic.Source = "jet " + d.Tag
+		}
+
+		if d.Start.FileID == config.MainFileID {
+			diagnostic.Range = doc.locRange(d.Start, d.End)
+		} else if d.Start.IsValid() {
+			diagnostic.Message = fmt.Sprintf("%s:%d:%d: %s", doc.filePath(d.Start.FileID), d.Start.Line, d.Start.Char, d.Message)
+		}
+
+		for _, note := range d.Notes {
+			if !note.Start.IsValid() {
+				diagnostic.Message += "\n" + note.Message
+				continue
+			}
+
+			diagnostic.RelatedInformation = append(diagnostic.RelatedInformation, DiagnosticRelatedInformation{
+				Location: doc.location(note.Start, note.End),
+				Message:  note.Message,
+			})
+		}
+
+		for _, fix := range d.Fixes {
+			if fix.Start.FileID != config.MainFileID {
+				continue
+			}
+
+			doc.actions = append(doc.actions, CodeAction{
+				Title:       fix.Message,
+				Kind:        codeActionKindQuickFix,
+				Diagnostics: []Diagnostic{diagnostic},
+				IsPreferred: len(d.Fixes) == 1,
+				Edit: &WorkspaceEdit{Changes: map[string][]TextEdit{
+					doc.uri: {{Range: doc.locRange(fix.Start, fix.End), NewText: fix.NewText}},
+				}},
+			})
+		}
+
+		diagnostics = append(diagnostics, diagnostic)
+	}
+
+	return diagnostics
+}
+
+// Returns the identifier of the document at the specified position
+// and its symbol.
+func (doc *document) identAt(pos Position) (*ast.Ident, checker.Symbol) {
+	if doc.module == nil {
+		return nil, nil
+	}
+
+	column := doc.column(config.MainFileID, pos.Line+1, pos.Character)
+	contains := func(ident *ast.Ident) bool {
+		return ident.Start.FileID == config.MainFileID &&
+			ident.Start.Line == pos.Line+1 &&
+			ident.Start.Char <= column+1 &&
+			column <= ident.End.Char
+	}
+
+	for el := doc.module.Defs.Front(); el != nil; el = el.Next() {
+		if contains(el.Key) {
+			return el.Key, el.Value
+		}
+	}
+
+	for ident, sym := range doc.module.Uses {
+		if contains(ident) {
+			return ident, sym
+		}
+	}
+
+	return nil, nil
+}
+
+func (doc *document) hover(pos Position) *Hover {
+	ident, sym := doc.identAt(pos)
+	if sym == nil {
+		return nil
+	}
+
+	buf := strings.Builder{}
+	buf.WriteString("```jet\n")
+	buf.WriteString(signature(sym, doc.module.TypeOf(ident)))
+	buf.WriteString("\n```")
+
+	if docString := strings.TrimSpace(doc.docOf(sym)); docString != "" {
+		buf.WriteString("\n\n")
+		buf.WriteString(docString)
+	}
+
+	r := doc.locRange(ident.Start, ident.End)
+
+	return &Hover{
+		Contents: MarkupContent{Kind: "markdown", Value: buf.String()},
+		Range:    &r,
+	}
+}
+
+func (doc *document) definition(pos Position) *Location {
+	_, sym := doc.identAt(pos)
+	if sym == nil {
+		return nil
+	}
+
+	if m, ok := sym.(*checker.Module); ok {
+		loc := Location{URI: pathToURI(doc.filePath(m.FileID()))}
+		return &loc
+	}
+
+	if ident := sym.Ident(); ident != nil && ident.Start.IsValid() {
+		loc := doc.location(ident.Start, ident.End)
+		return &loc
+	}
+
+	return nil
+}
+
+// Returns the locations of all usages of the symbol at the specified
+// position in the document and all of its imports.
+func (doc *document) references(pos Position, includeDecl bool) []Location {
+	locs := []Location{}
+
+	_, sym := doc.identAt(pos)
+	if sym == nil {
+		return locs
+	}
+
+	idents := []*ast.Ident{}
+
+	for _, m := range doc.modules {
+		if includeDecl {
+			for el := m.Defs.Front(); el != nil; el = el.Next() {
+				if el.Value == sym {
+					idents = append(idents, el.Key)
+				}
+			}
+		}
+
+		for ident, used := range m.Uses {
+			if used == sym {
+				idents = append(idents, ident)
+			}
+		}
+	}
+
+	slices.SortFunc(idents, func(a, b *ast.Ident) int {
+		return cmp.Or(
+			cmp.Compare(a.Start.FileID, b.Start.FileID),
+			cmp.Compare(a.Start.Offset, b.Start.Offset),
+		)
+	})
+
+	for _, ident := range idents {
+		locs = append(locs, doc.location(ident.Start, ident.End))
+	}
+
+	return locs
+}
+
+// Returns the quick fixes of the diagnostics that overlap the range.
+func (doc *document) codeActions(r Range) []CodeAction {
+	actions := []CodeAction{}
+
+	for _, action := range doc.actions {
+		if overlaps(action.Diagnostics[0].Range, r) {
+			actions = append(actions, action)
+		}
+	}
+
+	return actions
+}
+
+// Returns the symbols declared in the module scope of the document.
+func (doc *document) symbols() []DocumentSymbol {
+	symbols := []DocumentSymbol{}
+
+	if doc.module == nil {
+		return symbols
+	}
+
+	for el := doc.module.Defs.Front(); el != nil; el = el.Next() {
+		ident, sym := el.Key, el.Value
+
+		if sym.Owner() != doc.module.Scope || ident.Start.FileID != config.MainFileID {
+			continue
+		}
+
+		symbol := DocumentSymbol{
+			Name:           ident.Name,
+			Kind:           symbolKind(sym),
+			Range:          doc.locRange(ident.Start, ident.End),
+			SelectionRange: doc.locRange(ident.Start, ident.End),
+		}
+
+		if node := sym.Node(); node != nil && node.Pos().IsValid() {
+			symbol.Range = doc.locRange(node.Pos(), node.LocEnd())
+		}
+
+		if t := sym.Type(); t != nil {
+			symbol.Detail = t.String()
+		}
+
+		symbols = append(symbols, symbol)
+	}
+
+	return symbols
+}
+
+// Returns the doc comment of the declaration of the symbol.
+func (doc *document) docOf(sym checker.Symbol) string {
+	switch node := sym.Node().(type) {
+	case ast.Decl:
+		return node.Doc()
+
+	case *ast.Binding:
+		return node.Doc()
+	}
+
+	for _, m := range doc.modules {
+		moduleDecl, _ := m.Node().(*ast.ModuleDecl)
+		if moduleDecl == nil {
+			continue
+		}
+
+		list, _ := moduleDecl.Body.(*ast.List)
+		if list == nil {
+			continue
+		}
+
+		for _, node := range list.Nodes {
+			if decl, ok := node.(ast.Decl); ok && decl.Ident() == sym.Ident() {
+				return decl.Doc()
+			}
+		}
+	}
+
+	return ""
+}
+
+func (doc *document) filePath(fileID config.FileID) string {
+	return doc.cfg.Files[fileID].Path
+}
+
+func (doc *document) location(start, end token.Loc) Location {
+	return Location{
+		URI:   pathToURI(doc.filePath(start.FileID)),
+		Range: doc.locRange(start, end),
+	}
+}
+
+// Returns the signature of the symbol displayed by the hover.
+func signature(sym checker.Symbol, t types.Type) string {
+	switch sym := sym.(type) {
+	case *checker.Func:
+		return "func " + sym.Name() + strings.TrimPrefix(sym.Type().String(), "func")
+
+	case *checker.Struct:
+		return "struct " + sym.Name()
+
+	case *checker.Enum:
+		return "enum " + sym.Name()
+
+	case *checker.TypeAlias:
+		return "alias " + sym.Name()
+
+	case *checker.Module:
+		return "module " + sym.Name()
+
+	case *checker.Const:
+		if sym.Value() != nil {
+			return fmt.Sprintf("const %s: %s = %s", sym.Name(), sym.Type(), sym.Value())
+		}
+	}
+
+	if t == nil {
+		return sym.Name()
+	}
+
+	return fmt.Sprintf("%s: %s", sym.Name(), t)
+}
+
+func symbolKind(sym checker.Symbol) SymbolKind {
+	switch sym := sym.(type) {
+	case *checker.Func:
+		return SymbolKindFunction
+
+	case *checker.Struct:
+		return SymbolKindStruct
+
+	case *checker.Enum:
+		return SymbolKindEnum
+
+	case *checker.TypeAlias:
+		return SymbolKindClass
+
+	case *checker.Module:
+		return SymbolKindModule
+
+	case *checker.Const:
+		return SymbolKindConstant
+
+	case *checker.Var:
+		if sym.IsField() {
+			return SymbolKindField
+		}
+
+		return SymbolKindVariable
+
+	default:
+		return SymbolKindVariable
+	}
+}
+
+// Converts the location to the range. The end location is inclusive.
+func (doc *document) locRange(start, end token.Loc) Range {
+	if !start.IsValid() {
+		return Range{}
+	}
+
+	if !end.IsValid() {
+		end = start
+	}
+
+	return Range{
+		Start: Position{start.Line - 1, doc.character(start.FileID, start.Line, start.Char-1)},
+		End:   Position{end.Line - 1, doc.character(end.FileID, end.Line, end.Char)},
+	}
+}
+
+// Converts the zero-based byte column of the line to the character
+// offset in the position encoding of the client.
+func (doc *document) character(fileID config.FileID, line, column uint32) uint32 {
+	if doc.utf8 {
+		return column
+	}
+
+	text := doc.line(fileID, line)
+	if int(column) > len(text) {
+		return utf16Len(text) + column - uint32(len(text))
+	}
+
+	return utf16Len(text[:column])
+}
+
+// Converts the character offset in the position encoding of the client
+// to the zero-based byte column of the line.
+func (doc *document) column(fileID config.FileID, line, character uint32) uint32 {
+	if doc.utf8 {
+		return character
+	}
+
+	text := doc.line(fileID, line)
+	units := uint32(0)
+
+	for i, r := range string(text) {
+		if units >= character {
+			return uint32(i)
+		}
+
+		units += utf16RuneLen(r)
+	}
+
+	return uint32(len(text)) + character - min(units, character)
+}
+
+// Returns the line of the file without the line break. The line
+// number is one-based.
+func (doc *document) line(fileID config.FileID, n uint32) []byte {
+	lines, ok := doc.lines[fileID]
+
+	if !ok {
+		if fileInfo, ok := doc.cfg.Files[fileID]; ok && fileInfo.Buf != nil {
+			lines = splitLines(fileInfo.Buf.Bytes())
+		}
+
+		if doc.lines == nil {
+			doc.lines = map[config.FileID][][]byte{}
+		}
+
+		doc.lines[fileID] = lines
+	}
+
+	if n == 0 || int(n) > len(lines) {
+		return nil
+	}
+
+	return lines[n-1]
+}
+
+// Splits the text into lines the same way the scanner does: "\n",
+// "\r\n" and "\r" are line breaks.
+func splitLines(text []byte) [][]byte {
+	lines := [][]byte{}
+
+	for {
+		i := bytes.IndexAny(text, "\r\n")
+		if i < 0 {
+			return append(lines, text)
+		}
+
+		lines = append(lines, text[:i])
+
+		if text[i] == '\r' && i+1 < len(text) && text[i+1] == '\n' {
+			i++
+		}
+
+		text = text[i+1:]
+	}
+}
+
+// Returns the number of UTF-16 code units of the text.
+func utf16Len(text []byte) uint32 {
+	n := uint32(0)
+
+	for _, r := range string(text) {
+		n += utf16RuneLen(r)
+	}
+
+	return n
+}
+
+func utf16RuneLen(r rune) uint32 {
+	if r >= 0x10000 {
+		return 2
+	}
+
+	return 1
+}
+
+// Reports whether the ranges have at least one common position.
+// Empty ranges are considered to contain their start position.
+func overlaps(a, b Range) bool {
+	return !positionLess(a.End, b.Start) && !positionLess(b.End, a.Start)
+}
+
+func positionLess(a, b Position) bool {
+	return a.Line < b.Line || a.Line == b.Line && a.Character < b.Character
+}
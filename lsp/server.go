@@ -0,0 +1,269 @@
+// Package lsp implements the Language Server Protocol for Jet.
+//
+// The server communicates over a single stream (usually stdio) and
+// supports full document synchronization, diagnostics, hover,
+// go-to-definition, find-references and document symbols.
+package lsp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/saffage/jet/config"
+	"github.com/saffage/jet/internal/report"
+)
+
+// Server is a language server. Each open document is checked
+// separately in its own environment, imports are read from disk.
+type Server struct {
+	// Path to the core library. If empty, the path is determined
+	// by [config.CoreLibPath] for each document.
+	LibPath string
+
+	conn             *conn
+	docs             map[string]*document
+	positionEncoding string
+	initialized      bool
+	shutdown         bool
+}
+
+func NewServer(libPath string) *Server {
+	return &Server{
+		LibPath: libPath,
+		docs:    map[string]*document{},
+	}
+}
+
+// Serves the requests read from 'r' and writes the responses to 'w'
+// until the 'exit' notification is received or the input is closed.
+//
+// Nothing else must be written to 'w' while the server is running.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	s.conn = newConn(r, w)
+
+	for {
+		msg, err := s.conn.read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			var respErr *responseError
+			if errors.As(err, &respErr) {
+				if err := s.conn.reply(nil, nil, respErr); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			return err
+		}
+
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return errors.New("'exit' notification received before 'shutdown' request")
+			}
+
+			return nil
+		}
+
+		result, err := s.handle(msg)
+
+		if msg.ID == nil {
+			if err != nil {
+				report.TaggedWarningf("lsp", "%s: %s", msg.Method, err.Error())
+			}
+
+			continue
+		}
+
+		if err := s.conn.reply(msg.ID, result, err); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handle(msg *message) (any, error) {
+	if !s.initialized && msg.Method != "initialize" {
+		return nil, &responseError{codeServerNotInitialized, "the server is not initialized"}
+	}
+
+	switch msg.Method {
+	case "initialize":
+		params := InitializeParams{}
+		if len(msg.Params) != 0 {
+			if err := unmarshalParams(msg, &params); err != nil {
+				return nil, err
+			}
+		}
+
+		s.initialized = true
+		s.positionEncoding = positionEncodingUTF16
+
+		if slices.Contains(params.Capabilities.General.PositionEncodings, positionEncodingUTF8) {
+			s.positionEncoding = positionEncodingUTF8
+		}
+
+		return InitializeResult{
+			Capabilities: ServerCapabilities{
+				PositionEncoding:       s.positionEncoding,
+				TextDocumentSync:       textDocumentSyncFull,
+				HoverProvider:          true,
+				DefinitionProvider:     true,
+				ReferencesProvider:     true,
+				DocumentSymbolProvider: true,
+				CodeActionProvider:     true,
+			},
+			ServerInfo: ServerInfo{Name: "jet", Version: config.Version},
+		}, nil
+
+	case "initialized":
+		return nil, nil
+
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+
+	case "textDocument/didOpen":
+		params := DidOpenTextDocumentParams{}
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+
+		return nil, s.update(params.TextDocument.URI, params.TextDocument.Version, params.TextDocument.Text)
+
+	case "textDocument/didChange":
+		params := DidChangeTextDocumentParams{}
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+
+		if len(params.ContentChanges) == 0 {
+			return nil, nil
+		}
+
+		text := params.ContentChanges[len(params.ContentChanges)-1].Text
+		return nil, s.update(params.TextDocument.URI, params.TextDocument.Version, text)
+
+	case "textDocument/didClose":
+		params := DidCloseTextDocumentParams{}
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+
+		delete(s.docs, params.TextDocument.URI)
+
+		// Clear the diagnostics of the closed document.
+		return nil, s.conn.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+			URI:         params.TextDocument.URI,
+			Diagnostics: []Diagnostic{},
+		})
+
+	case "textDocument/hover":
+		params := TextDocumentPositionParams{}
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+
+		if doc := s.docs[params.TextDocument.URI]; doc != nil {
+			if hover := doc.hover(params.Position); hover != nil {
+				return hover, nil
+			}
+		}
+
+		return nil, nil
+
+	case "textDocument/definition":
+		params := TextDocumentPositionParams{}
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+
+		if doc := s.docs[params.TextDocument.URI]; doc != nil {
+			if loc := doc.definition(params.Position); loc != nil {
+				return loc, nil
+			}
+		}
+
+		return nil, nil
+
+	case "textDocument/references":
+		params := ReferenceParams{}
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+
+		if doc := s.docs[params.TextDocument.URI]; doc != nil {
+			return doc.references(params.Position, params.Context.IncludeDeclaration), nil
+		}
+
+		return []Location{}, nil
+
+	case "textDocument/documentSymbol":
+		params := DocumentSymbolParams{}
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+
+		if doc := s.docs[params.TextDocument.URI]; doc != nil {
+			return doc.symbols(), nil
+		}
+
+		return []DocumentSymbol{}, nil
+
+	case "textDocument/codeAction":
+		params := CodeActionParams{}
+		if err := unmarshalParams(msg, &params); err != nil {
+			return nil, err
+		}
+
+		if doc := s.docs[params.TextDocument.URI]; doc != nil {
+			return doc.codeActions(params.Range), nil
+		}
+
+		return []CodeAction{}, nil
+
+	default:
+		if msg.ID == nil {
+			// Unknown notifications are ignored.
+			return nil, nil
+		}
+
+		return nil, &responseError{codeMethodNotFound, fmt.Sprintf("method '%s' is not supported", msg.Method)}
+	}
+}
+
+// Checks the new content of the document and publishes its diagnostics.
+func (s *Server) update(uri string, version int, text string) error {
+	path, err := uriToPath(uri)
+	if err != nil {
+		return &responseError{codeInvalidParams, err.Error()}
+	}
+
+	doc := &document{
+		uri:     uri,
+		path:    path,
+		version: version,
+		utf8:    s.positionEncoding == positionEncodingUTF8,
+	}
+	doc.check(s.LibPath, []byte(text))
+	s.docs[uri] = doc
+
+	return s.conn.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+		URI:         uri,
+		Version:     version,
+		Diagnostics: doc.diagnostics,
+	})
+}
+
+func unmarshalParams(msg *message, v any) error {
+	if err := json.Unmarshal(msg.Params, v); err != nil {
+		return &responseError{codeInvalidParams, err.Error()}
+	}
+
+	return nil
+}
@@ -0,0 +1,254 @@
+package lsp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type testClient struct {
+	buf    bytes.Buffer
+	nextID int
+}
+
+func (c *testClient) send(method string, params any) {
+	msg := map[string]any{"jsonrpc": "2.0", "method": method, "params": params}
+
+	if method != "initialized" && method != "exit" && !strings.HasPrefix(method, "textDocument/did") {
+		c.nextID++
+		msg["id"] = c.nextID
+	}
+
+	content, _ := json.Marshal(msg)
+	fmt.Fprintf(&c.buf, "Content-Length: %d\r\n\r\n%s", len(content), content)
+}
+
+// Runs the server and returns the received messages indexed by
+// the request ID. Notifications are indexed by the method.
+func (c *testClient) run(t *testing.T) map[string]json.RawMessage {
+	out := bytes.Buffer{}
+	libPath, _ := filepath.Abs("../lib")
+
+	if err := NewServer(libPath).Serve(&c.buf, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	received := map[string]json.RawMessage{}
+	conn := newConn(&out, nil)
+
+	for {
+		msg, err := conn.read()
+		if err != nil {
+			break
+		}
+
+		if msg.ID != nil {
+			content, _ := json.Marshal(msg.Result)
+			received[string(*msg.ID)] = content
+		} else {
+			received[msg.Method] = msg.Params
+		}
+	}
+
+	return received
+}
+
+func TestServer(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Main.jet")
+	text := "var counter = 1\n\nfunc main() {\n    @print(counter)\n}\n"
+
+	if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	uri := pathToURI(path)
+	doc := map[string]any{"uri": uri}
+	usage := map[string]any{"line": 3, "character": 12}
+
+	c := &testClient{}
+	c.send("initialize", map[string]any{})
+	c.send("textDocument/didOpen", map[string]any{
+		"textDocument": map[string]any{"uri": uri, "languageId": "jet", "version": 1, "text": text},
+	})
+	c.send("textDocument/hover", map[string]any{"textDocument": doc, "position": usage})
+	c.send("textDocument/definition", map[string]any{"textDocument": doc, "position": usage})
+	c.send("textDocument/references", map[string]any{
+		"textDocument": doc,
+		"position":     usage,
+		"context":      map[string]any{"includeDeclaration": true},
+	})
+	c.send("textDocument/documentSymbol", map[string]any{"textDocument": doc})
+	c.send("shutdown", nil)
+	c.send("exit", nil)
+
+	received := c.run(t)
+
+	diagnostics := PublishDiagnosticsParams{}
+	if err := json.Unmarshal(received["textDocument/publishDiagnostics"], &diagnostics); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(diagnostics.Diagnostics) != 0 {
+		t.Errorf("unexpected diagnostics: %+v", diagnostics.Diagnostics)
+	}
+
+	hover := Hover{}
+	if err := json.Unmarshal(received["2"], &hover); err != nil {
+		t.Fatal(err)
+	}
+
+	if hover.Contents.Value != "```jet\ncounter: i32\n```" {
+		t.Errorf("unexpected hover: %q", hover.Contents.Value)
+	}
+
+	definition := Location{}
+	if err := json.Unmarshal(received["3"], &definition); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := (Range{Position{0, 4}, Position{0, 11}}); definition.URI != uri || definition.Range != want {
+		t.Errorf("unexpected definition: %+v", definition)
+	}
+
+	references := []Location{}
+	if err := json.Unmarshal(received["4"], &references); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(references) != 2 || references[1].Range.Start != (Position{3, 11}) {
+		t.Errorf("unexpected references: %+v", references)
+	}
+
+	symbols := []DocumentSymbol{}
+	if err := json.Unmarshal(received["5"], &symbols); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(symbols) != 2 || symbols[0].Name != "counter" || symbols[1].Kind != SymbolKindFunction {
+		t.Errorf("unexpected symbols: %+v", symbols)
+	}
+}
+
+func TestServerDiagnostics(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Main.jet")
+	uri := pathToURI(path)
+
+	c := &testClient{}
+	c.send("initialize", map[string]any{})
+	c.send("textDocument/didOpen", map[string]any{
+		"textDocument": map[string]any{"uri": uri, "languageId": "jet", "version": 1, "text": "func main() { x }\n"},
+	})
+
+	received := c.run(t)
+
+	diagnostics := PublishDiagnosticsParams{}
+	if err := json.Unmarshal(received["textDocument/publishDiagnostics"], &diagnostics); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(diagnostics.Diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, have %+v", diagnostics.Diagnostics)
+	}
+
+	d := diagnostics.Diagnostics[0]
+	if d.Message != "identifier is undefined" || d.Range != (Range{Position{0, 14}, Position{0, 15}}) {
+		t.Errorf("unexpected diagnostic: %+v", d)
+	}
+}
+
+func TestServerCodeActions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Main.jet")
+	uri := pathToURI(path)
+
+	c := &testClient{}
+	c.send("initialize", map[string]any{})
+	c.send("textDocument/didOpen", map[string]any{
+		"textDocument": map[string]any{"uri": uri, "languageId": "jet", "version": 1, "text": "var count = 1\n\nfunc main() { cuont }\n"},
+	})
+	c.send("textDocument/codeAction", map[string]any{
+		"textDocument": map[string]any{"uri": uri},
+		"range":        Range{Position{2, 16}, Position{2, 16}},
+	})
+
+	received := c.run(t)
+
+	actions := []CodeAction{}
+	if err := json.Unmarshal(received["2"], &actions); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(actions) != 1 || actions[0].Title != "did you mean 'count'?" || actions[0].Edit == nil {
+		t.Fatalf("unexpected code actions: %+v", actions)
+	}
+
+	edits := actions[0].Edit.Changes[uri]
+	if len(edits) != 1 || edits[0].NewText != "count" || edits[0].Range != (Range{Position{2, 14}, Position{2, 19}}) {
+		t.Errorf("unexpected edits: %+v", edits)
+	}
+}
+
+func TestServerPositionEncoding(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Main.jet")
+	uri := pathToURI(path)
+	text := "var counter = 1\n\nfunc main() {\n\t@print(\"é😀\"); @print(counter); x\n}\n"
+
+	tests := []struct {
+		encodings []string
+		encoding  string
+		counter   uint32 // Character offset of 'counter' in the 4th line.
+		x         uint32 // Character offset of 'x' in the 4th line.
+	}{
+		{nil, "utf-16", 23, 33},
+		{[]string{"utf-16", "utf-8"}, "utf-8", 26, 36},
+	}
+
+	for _, test := range tests {
+		c := &testClient{}
+		c.send("initialize", map[string]any{
+			"capabilities": map[string]any{"general": map[string]any{"positionEncodings": test.encodings}},
+		})
+		c.send("textDocument/didOpen", map[string]any{
+			"textDocument": map[string]any{"uri": uri, "languageId": "jet", "version": 1, "text": text},
+		})
+		c.send("textDocument/hover", map[string]any{
+			"textDocument": map[string]any{"uri": uri},
+			"position":     Position{3, test.counter + 3},
+		})
+
+		received := c.run(t)
+
+		result := InitializeResult{}
+		if err := json.Unmarshal(received["1"], &result); err != nil {
+			t.Fatal(err)
+		}
+
+		if result.Capabilities.PositionEncoding != test.encoding {
+			t.Errorf("expected position encoding %q, have %q", test.encoding, result.Capabilities.PositionEncoding)
+		}
+
+		diagnostics := PublishDiagnosticsParams{}
+		if err := json.Unmarshal(received["textDocument/publishDiagnostics"], &diagnostics); err != nil {
+			t.Fatal(err)
+		}
+
+		want := Range{Position{3, test.x}, Position{3, test.x + 1}}
+		if len(diagnostics.Diagnostics) != 1 || diagnostics.Diagnostics[0].Range != want {
+			t.Errorf("%s: unexpected diagnostics: %+v", test.encoding, diagnostics.Diagnostics)
+		}
+
+		hover := Hover{}
+		if err := json.Unmarshal(received["2"], &hover); err != nil {
+			t.Fatal(err)
+		}
+
+		want = Range{Position{3, test.counter}, Position{3, test.counter + 7}}
+		if hover.Range == nil || *hover.Range != want {
+			t.Errorf("%s: unexpected hover: %+v", test.encoding, hover)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package lsp
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+)
+
+// Converts the 'file' URI to the file path.
+func uriToPath(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+
+	if u.Scheme != "file" {
+		return "", fmt.Errorf("unsupported URI scheme '%s'", u.Scheme)
+	}
+
+	return filepath.FromSlash(u.Path), nil
+}
+
+// Converts the file path to the 'file' URI.
+func pathToURI(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+
+	u := url.URL{Scheme: "file", Path: filepath.ToSlash(path)}
+	return u.String()
+}
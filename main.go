@@ -4,8 +4,8 @@ import (
 	"os"
 
 	"github.com/davecgh/go-spew/spew"
-	"github.com/saffage/jet/internal/jet"
 	"github.com/saffage/jet/internal/report"
+	"github.com/saffage/jet/jet"
 )
 
 func main() {
@@ -14,9 +14,14 @@ func main() {
 	spew.Config.DisableCapacities = true
 	spew.Config.DisablePointerAddresses = true
 
+	os.Exit(run())
+}
+
+func run() int {
+	defer report.Flush()
 	defer catchInternalErrors()
 
-	jet.ProcessArgs(os.Args)
+	return jet.ProcessArgs(os.Args)
 }
 
 func catchInternalErrors() {
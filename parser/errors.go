@@ -11,6 +11,7 @@ import (
 
 type Error struct {
 	Start, End token.Loc
+	Code       report.Code
 	Message    string
 	Notes      []string
 }
@@ -30,38 +31,62 @@ func NewErrorf(start, end token.Loc, format string, args ...any) Error {
 func (e Error) Error() string { return e.Message }
 
 func (e Error) Report() {
-	report.TaggedErrorAt("parser", e.Start, e.End, e.Message)
+	report.Emit(e.Diagnostic())
+}
+
+func (e Error) Diagnostic() *report.Diagnostic {
+	d := &report.Diagnostic{
+		Kind:    report.KindError,
+		Tag:     "parser",
+		Code:    e.Code,
+		Message: e.Message,
+		Start:   e.Start,
+		End:     e.End,
+	}
 
 	for _, note := range e.Notes {
-		report.TaggedNote("parser", note)
+		d.Notes = append(d.Notes, &report.Diagnostic{
+			Kind:    report.KindNote,
+			Tag:     "parser",
+			Message: note,
+		})
 	}
+
+	return d
 }
 
 func (p *Parser) addError(err error) {
+	// Only the first error on the line is reported, the
+	// others are most likely caused by it.
+	if e, ok := err.(Error); ok && len(p.errors) > 0 {
+		if last, ok := p.errors[len(p.errors)-1].(Error); ok &&
+			last.Start.FileID == e.Start.FileID &&
+			last.Start.Line == e.Start.Line {
+			return
+		}
+	}
+
 	p.errors = append(p.errors, err)
 }
 
-func (p *Parser) error(start, end token.Loc, message string) {
+func (p *Parser) error(start, end token.Loc, code report.Code, message string) {
 	if p.flags&Trace != 0 {
 		p.trace()
 		defer p.untrace()
 	}
 
-	p.addError(NewError(start, end, message))
+	err := NewError(start, end, message)
+	err.Code = code
+	p.addError(err)
 }
 
-func (p *Parser) errorf(start, end token.Loc, format string, args ...any) {
-	if p.flags&Trace != 0 {
-		p.trace()
-		defer p.untrace()
-	}
-
-	p.addError(NewErrorf(start, end, format, args...))
+func (p *Parser) errorf(start, end token.Loc, code report.Code, format string, args ...any) {
+	p.error(start, end, code, fmt.Sprintf(format, args...))
 }
 
 func (p *Parser) errorExpected(start, end token.Loc, message string) {
 	message = fmt.Sprintf("expected %s, found %s", message, p.tok.Kind.UserString())
-	p.error(start, end, message)
+	p.error(start, end, report.CodeUnexpectedToken, message)
 }
 
 func (p *Parser) errorExpectedToken(start, end token.Loc, tokens ...token.Kind) {
@@ -76,7 +101,7 @@ func (p *Parser) errorExpectedToken(start, end token.Loc, tokens ...token.Kind)
 	}
 
 	message := fmt.Sprintf("expected %s, found %s", strings.Join(tokenStrs, " or "), p.tok.Kind.UserString())
-	p.error(start, end, message)
+	p.error(start, end, report.CodeUnexpectedToken, message)
 }
 
 func (p *Parser) skipTo(to ...token.Kind) (start, end token.Loc) {
@@ -93,8 +118,24 @@ func (p *Parser) skipTo(to ...token.Kind) (start, end token.Loc) {
 
 	to = append(to, token.EOF)
 	start = p.tok.Start
+	depth := 0
+
+	// Nested brackets are skipped entirely. The tokens are never skipped
+	// past the start of the next declaration, so the unclosed bracket
+	// doesn't hide the rest of the module.
+	for p.tok.Kind != token.EOF && (depth > 0 || !slices.Contains(to, p.tok.Kind)) {
+		if p.tok.Kind == token.NewLine && p.isDeclAfter(p.current) {
+			break
+		}
+
+		switch p.tok.Kind {
+		case token.LParen, token.LBracket, token.LCurly:
+			depth++
+
+		case token.RParen, token.RBracket, token.RCurly:
+			depth = max(depth-1, 0)
+		}
 
-	for !slices.Contains(to, p.tok.Kind) {
 		p.next()
 	}
 
@@ -103,7 +144,53 @@ func (p *Parser) skipTo(to ...token.Kind) (start, end token.Loc) {
 	return
 }
 
+// Skips the tokens until the new line before the next declaration
+// of the module. The declaration must start at the beginning of the line.
+func (p *Parser) skipToDecl() (start, end token.Loc) {
+	start, end = p.tok.Start, p.tok.End
+
+	for p.tok.Kind != token.EOF {
+		if p.tok.Kind == token.NewLine && p.isDeclAfter(p.current) {
+			break
+		}
+
+		end = p.tok.End
+		p.next()
+	}
+
+	return
+}
+
+// Reports whether the next non-empty line after the token with the
+// specified index starts with a declaration. Indented declarations are
+// ignored, since they are most likely inside of the invalid one.
+func (p *Parser) isDeclAfter(index int) bool {
+	for _, tok := range p.tokens[index+1:] {
+		switch tok.Kind {
+		case token.NewLine, token.Whitespace, token.Tab, token.Comment:
+			continue
+
+		default:
+			return tok.Start.Char == 1 && slices.Contains(startOfDeclKinds, tok.Kind)
+		}
+	}
+
+	return false
+}
+
 var (
+	startOfDeclKinds = []token.Kind{
+		token.At,
+		token.KwVar,
+		token.KwVal,
+		token.KwConst,
+		token.KwFunc,
+		token.KwStruct,
+		token.KwEnum,
+		token.KwAlias,
+		token.KwModule,
+		token.KwImport,
+	}
 	endOfStmtKinds = []token.Kind{
 		token.Semicolon,
 		token.NewLine,
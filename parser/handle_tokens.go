@@ -5,6 +5,7 @@ import (
 	"slices"
 	"strings"
 
+	"github.com/saffage/jet/ast"
 	"github.com/saffage/jet/token"
 )
 
@@ -19,24 +20,22 @@ func (p *Parser) next() {
 
 	p.tok = p.tokens[p.current]
 
-	// if p.commentGroup != nil && p.tok.End.Line > p.commentGroup.LocEnd().Line+1 {
-	// 	p.commentGroup = nil
-	// }
+	switch p.tok.Kind {
+	case token.Comment:
+		if strings.HasPrefix(p.tok.Data, "##") && p.isFirstOnLine(p.current) {
+			p.addDocComment()
+		}
 
-	if p.tok.Kind == token.Comment {
-		// 	if strings.HasPrefix(p.tok.Data, "##") {
-		// 		if p.commentGroup == nil {
-		// 			p.commentGroup = &ast.CommentGroup{}
-		// 		}
+		p.next()
+		return
 
-		// 		p.commentGroup.Comments = append(p.commentGroup.Comments, &ast.Comment{
-		// 			Data:  p.tok.Data[2:],
-		// 			Start: p.tok.Start,
-		// 			End:   p.tok.End,
-		// 		})
-		// 	}
+	case token.NewLine, token.Whitespace, token.Tab:
 
-		p.next()
+	default:
+		// Doc comments must be placed right before the declaration.
+		if p.commentGroup != nil && p.tok.Start.Line > p.commentGroup.LocEnd().Line+1 {
+			p.commentGroup = nil
+		}
 	}
 
 	if p.flags&SkipWhitespace != 0 &&
@@ -49,6 +48,65 @@ func (p *Parser) next() {
 	}
 }
 
+// Appends the current comment token to the group of doc comments.
+// Comments on non-adjacent lines start a new group.
+func (p *Parser) addDocComment() {
+	comment := &ast.Comment{
+		Data:  p.tok.Data[2:],
+		Start: p.tok.Start,
+		End:   p.tok.End,
+	}
+
+	if p.commentGroup == nil || comment.Start.Line > p.commentGroup.LocEnd().Line+1 {
+		p.commentGroup = &ast.CommentGroup{}
+	}
+
+	p.commentGroup.Comments = append(p.commentGroup.Comments, comment)
+}
+
+// Returns the doc comments placed right before the current token
+// and resets them, so they are attached to one declaration only.
+func (p *Parser) takeDoc() *ast.CommentGroup {
+	group := p.commentGroup
+	p.commentGroup = nil
+	return group
+}
+
+// Reports whether the token with the specified index is the first
+// token on its line.
+func (p *Parser) isFirstOnLine(index int) bool {
+	for i := index - 1; i >= 0; i-- {
+		switch p.tokens[i].Kind {
+		case token.Whitespace, token.Tab:
+			continue
+
+		case token.NewLine:
+			return true
+
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// Returns the kind of the token after the current one. Whitespace and
+// comments are skipped.
+func (p *Parser) peek() token.Kind {
+	for i := p.current + 1; i < len(p.tokens); i++ {
+		switch p.tokens[i].Kind {
+		case token.Whitespace, token.Tab, token.Comment:
+			continue
+
+		default:
+			return p.tokens[i].Kind
+		}
+	}
+
+	return token.EOF
+}
+
 func (p *Parser) match(kinds ...token.Kind) bool {
 	return len(kinds) > 0 && slices.Contains(kinds, p.tok.Kind)
 }
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"github.com/saffage/jet/ast"
+	"github.com/saffage/jet/internal/report"
 	"github.com/saffage/jet/token"
 )
 
@@ -77,6 +78,7 @@ func (p *Parser) parseStmt() ast.Node {
 		p.next()
 	}
 
+	doc := p.takeDoc()
 	attributes := p.parseAttributes()
 
 	for p.tok.Kind == token.NewLine {
@@ -89,7 +91,7 @@ func (p *Parser) parseStmt() ast.Node {
 	case token.Semicolon:
 		node = p.parseEmptyStmt()
 
-	case token.KwVar:
+	case token.KwVar, token.KwVal:
 		node = p.parseVarDecl()
 
 	case token.KwConst:
@@ -101,6 +103,16 @@ func (p *Parser) parseStmt() ast.Node {
 	case token.KwWhile:
 		return p.parseWhile()
 
+	case token.KwFor:
+		return p.parseFor()
+
+	case token.Ident:
+		if p.peek() == token.Colon {
+			return p.parseLabeledLoop()
+		}
+
+		node = p.parseExpr()
+
 	case token.KwFunc:
 		node = p.parseFuncDecl()
 
@@ -136,19 +148,42 @@ func (p *Parser) parseStmt() ast.Node {
 			p.error(
 				attributes.Pos(),
 				attributes.LocEnd(),
+				report.CodeMisplacedAttributes,
 				"unexpected attribute list (only a declaration can have attributes)",
 			)
 		}
 	}
 
-	// if decl, isDecl := node.(ast.Decl); isDecl {
-	// 	setDoc(decl, p.commentGroup)
-	// }
-	// p.commentGroup = nil
+	if decl, isDecl := node.(ast.Decl); isDecl && doc != nil {
+		setDoc(decl, doc)
+	}
 
 	return node
 }
 
+// Parses the statement of the module. On error, the tokens are skipped
+// until the next declaration, so the rest of the invalid declaration
+// doesn't produce more errors.
+func (p *Parser) parseModuleStmt() ast.Node {
+	if p.flags&Trace != 0 {
+		p.trace()
+		defer p.untrace()
+	}
+
+	for p.tok.Kind == token.NewLine {
+		p.next()
+	}
+
+	start := p.tok.Start
+
+	if node := p.parseStmt(); node != nil {
+		return node
+	}
+
+	p.skipToDecl()
+	return &ast.BadNode{Loc: start}
+}
+
 func (p *Parser) parseEmptyStmt() ast.Node {
 	if p.flags&Trace != 0 {
 		p.trace()
@@ -156,8 +191,9 @@ func (p *Parser) parseEmptyStmt() ast.Node {
 	}
 
 	if p.tok.Kind == token.Semicolon {
+		loc := p.tok.Start
 		p.next()
-		return &ast.Empty{Loc: p.tok.Start}
+		return &ast.Empty{Loc: loc}
 	}
 
 	return nil
@@ -177,6 +213,9 @@ func (p *Parser) parseExpr() ast.Node {
 	case token.KwIf:
 		return p.parseIf()
 
+	case token.KwMatch:
+		return p.parseMatch()
+
 	case token.LCurly:
 		return p.parseBlock()
 
@@ -296,10 +335,17 @@ func (p *Parser) parseBinaryExpr(lhs ast.Node, precedence token.Precedence) ast.
 		case token.KwOr:
 			binaryOpKind = ast.OperatorOr
 
+		case token.Dot2:
+			binaryOpKind = ast.OperatorRangeIncl
+
+		case token.Dot2Less:
+			binaryOpKind = ast.OperatorRangeExcl
+
 		default:
 			p.errorf(
 				tok.Start,
 				tok.End,
+				report.CodeUnexpectedToken,
 				"%s cannot be used in the binary expression",
 				tok.Kind.UserString(),
 			)
@@ -483,17 +529,21 @@ func (p *Parser) parseSuffixExpr(x ast.Node) ast.Node {
 		// 	}
 
 		case token.LBracket:
-			x = &ast.Index{
-				X:    x,
-				Args: p.parseBracketList(p.parseExpr),
+			args := p.parseBracketList(p.parseExpr)
+			if args == nil {
+				return nil
 			}
 
+			x = &ast.Index{X: x, Args: args}
+
 		case token.LParen:
-			x = &ast.Call{
-				X:    x,
-				Args: p.parseParenList(p.parseExpr),
+			args := p.parseParenList(p.parseExpr)
+			if args == nil {
+				return nil
 			}
 
+			x = &ast.Call{X: x, Args: args}
+
 		default:
 			return x
 		}
@@ -618,7 +668,7 @@ func (p *Parser) parseVarDecl() ast.Node {
 		defer p.untrace()
 	}
 
-	tok := p.expect(token.KwVar)
+	tok := p.expect(token.KwVar, token.KwVal)
 	binding, ok := p.parseBinding().(*ast.Binding)
 
 	if !ok {
@@ -640,6 +690,7 @@ func (p *Parser) parseVarDecl() ast.Node {
 		Binding: binding,
 		Value:   value,
 		Loc:     tok.Start,
+		IsVal:   tok.Kind == token.KwVal,
 	}
 }
 
@@ -747,16 +798,31 @@ func (p *Parser) parseEnumDecl() ast.Node {
 		return nil
 	}
 
-	body := p.parseCurlyList(p.parseIdent)
+	memberDocs := map[*ast.Ident]*ast.CommentGroup{}
+	body := p.parseCurlyList(func() ast.Node {
+		doc := p.takeDoc()
+		ident := p.parseIdentNode()
+
+		if ident == nil {
+			return nil
+		}
+
+		if doc != nil {
+			memberDocs[ident] = doc
+		}
+
+		return ident
+	})
 
 	if body == nil {
 		return nil
 	}
 
 	return &ast.EnumDecl{
-		Name: name,
-		Body: body,
-		Loc:  tok.Start,
+		Name:       name,
+		Body:       body,
+		MemberDocs: memberDocs,
+		Loc:        tok.Start,
 	}
 }
 
@@ -853,6 +919,7 @@ func (p *Parser) parseBinding() ast.Node {
 		defer p.untrace()
 	}
 
+	doc := p.takeDoc()
 	attrs := p.parseAttributes()
 	name := p.parseIdentNode()
 
@@ -870,9 +937,10 @@ func (p *Parser) parseBinding() ast.Node {
 	// }
 
 	return &ast.Binding{
-		Attrs: attrs,
-		Name:  name,
-		Type:  typ,
+		Attrs:        attrs,
+		CommentGroup: doc,
+		Name:         name,
+		Type:         typ,
 	}
 }
 
@@ -932,7 +1000,7 @@ func (p *Parser) parseTypeName() ast.Node {
 
 	default:
 		start, end := p.skipTo()
-		p.error(start, end, "expected type name")
+		p.error(start, end, report.CodeUnexpectedToken, "expected type name")
 		return nil
 	}
 }
@@ -1086,7 +1154,7 @@ func (p *Parser) parseElse() ast.Node {
 
 			if body == nil {
 				start, end := p.skipTo()
-				p.error(start, end, "expected `if` clause or block after `else`")
+				p.error(start, end, report.CodeUnexpectedToken, "expected `if` clause or block after `else`")
 				return nil
 			}
 		}
@@ -1116,7 +1184,7 @@ func (p *Parser) parseIf() ast.Node {
 
 	if cond == nil {
 		start, end := p.skipTo()
-		p.error(start, end, "expected conditional expression for `if` clause")
+		p.error(start, end, report.CodeUnexpectedToken, "expected conditional expression for `if` clause")
 		return nil
 	}
 
@@ -1124,7 +1192,7 @@ func (p *Parser) parseIf() ast.Node {
 
 	if body == nil {
 		start, end := p.skipTo()
-		p.error(start, end, "expected body for 'if' clause")
+		p.error(start, end, report.CodeUnexpectedToken, "expected body for 'if' clause")
 		return nil
 	}
 
@@ -1143,6 +1211,84 @@ func (p *Parser) parseIf() ast.Node {
 	}
 }
 
+func (p *Parser) parseMatch() ast.Node {
+	if p.flags&Trace != 0 {
+		p.trace()
+		defer p.untrace()
+	}
+
+	tok := p.expect(token.KwMatch)
+	if tok == nil {
+		return nil
+	}
+
+	x := p.parseExpr()
+	if x == nil {
+		return nil
+	}
+
+	body := p.parseCurlyList(p.parseMatchArm)
+	if body == nil {
+		return nil
+	}
+
+	return &ast.Match{
+		X:    x,
+		Body: body,
+		Loc:  tok.Start,
+	}
+}
+
+// Parses `a, b => x` in the body of `match`.
+func (p *Parser) parseMatchArm() ast.Node {
+	if p.flags&Trace != 0 {
+		p.trace()
+		defer p.untrace()
+	}
+
+	patterns := &ast.ExprList{}
+
+	for {
+		// Patterns are parsed before '=>', so it's not an operator.
+		pattern := p.parseBinaryExpr(nil, token.ArrowPrec+1)
+		if pattern == nil {
+			return nil
+		}
+
+		patterns.Exprs = append(patterns.Exprs, pattern)
+
+		if p.consume(token.Comma) == nil {
+			break
+		}
+	}
+
+	if p.expect(token.FatArrow) == nil {
+		return nil
+	}
+
+	x := ast.Node(nil)
+
+	switch p.tok.Kind {
+	case token.KwReturn:
+		x = p.parseReturn()
+
+	case token.KwBreak, token.KwContinue:
+		x = p.parseBreakOrContinue()
+
+	default:
+		x = p.parseExpr()
+	}
+
+	if x == nil {
+		return nil
+	}
+
+	return &ast.MatchArm{
+		Patterns: patterns,
+		X:        x,
+	}
+}
+
 func (p *Parser) parseWhile() ast.Node {
 	if p.flags&Trace != 0 {
 		p.trace()
@@ -1171,6 +1317,88 @@ func (p *Parser) parseWhile() ast.Node {
 	}
 }
 
+// Parses `for value in x {}` or `for index, value in x {}`.
+func (p *Parser) parseFor() ast.Node {
+	if p.flags&Trace != 0 {
+		p.trace()
+		defer p.untrace()
+	}
+
+	tok := p.expect(token.KwFor)
+	if tok == nil {
+		return nil
+	}
+
+	index, value := (*ast.Ident)(nil), p.parseIdentNode()
+	if value == nil {
+		return nil
+	}
+
+	if p.consume(token.Comma) != nil {
+		index, value = value, p.parseIdentNode()
+		if value == nil {
+			return nil
+		}
+	}
+
+	if p.expect(token.KwIn) == nil {
+		return nil
+	}
+
+	x := p.parseExpr()
+	if x == nil {
+		return nil
+	}
+
+	body := p.parseCurlyList(p.parseStmt)
+	if body == nil {
+		return nil
+	}
+
+	return &ast.For{
+		Index: index,
+		Value: value,
+		X:     x,
+		Body:  body,
+		Loc:   tok.Start,
+	}
+}
+
+// Parses the loop with a label, e.g. `outer: while cond {}`.
+func (p *Parser) parseLabeledLoop() ast.Node {
+	if p.flags&Trace != 0 {
+		p.trace()
+		defer p.untrace()
+	}
+
+	label := p.parseIdentNode()
+	p.expect(token.Colon)
+
+	switch p.tok.Kind {
+	case token.KwWhile:
+		node, _ := p.parseWhile().(*ast.While)
+		if node == nil {
+			return nil
+		}
+
+		node.Label = label
+		return node
+
+	case token.KwFor:
+		node, _ := p.parseFor().(*ast.For)
+		if node == nil {
+			return nil
+		}
+
+		node.Label = label
+		return node
+
+	default:
+		p.errorExpectedToken(p.tok.Start, p.tok.End, token.KwWhile, token.KwFor)
+		return nil
+	}
+}
+
 func (p *Parser) parseReturn() ast.Node {
 	if p.flags&Trace != 0 {
 		p.trace()
@@ -1178,7 +1406,12 @@ func (p *Parser) parseReturn() ast.Node {
 	}
 
 	tok := p.expect(token.KwReturn)
-	x := p.parseExpr()
+	x := ast.Node(nil)
+
+	// Return without value.
+	if !p.match(endOfExprKinds...) && p.tok.Kind != token.EOF {
+		x = p.parseExpr()
+	}
 
 	return &ast.Return{
 		X:   x,
@@ -1239,7 +1472,7 @@ func (p *Parser) parseModule() ast.Node {
 		}
 	}
 
-	p.error(p.tok.Start, p.tok.End, "first statement in the file should be `module`")
+	p.error(p.tok.Start, p.tok.End, report.CodeUnexpectedToken, "first statement in the file should be `module`")
 	return nil
 }
 
@@ -1420,7 +1653,7 @@ func (p *Parser) parseStmtList() *ast.List {
 	}
 
 	if nodes, _ := p.listWithDelimiter(
-		p.parseStmt,
+		p.parseModuleStmt,
 		token.EOF,
 		token.Semicolon,
 		token.NewLine,
@@ -1483,13 +1716,19 @@ func (p *Parser) listWithDelimiter(
 			default:
 				// [parseFunc] set the correct node, but no separator was found.
 				// Report it and assign [ast.BadNode] instead.
-				p.error(node.Pos(), node.LocEnd(), "unterminated expression")
+				p.error(node.Pos(), node.LocEnd(), report.CodeUnclosedBracket, "unterminated expression")
 			}
 		}
 
 		// Something went wrong, advance to some delimiter and
 		// continue parsing elements until we find the [closing] token.
 		p.skipTo(append(separators, delimiter)...)
+
+		if p.tok.Kind == token.NewLine && p.isDeclAfter(p.current) {
+			// The list is never closed, the next declaration starts.
+			return nil, false
+		}
+
 		p.consume(separators...)
 		nodes = append(nodes, &ast.BadNode{Loc: nodeStart})
 	}
@@ -1523,7 +1762,7 @@ func (p *Parser) parseBracketedList(
 		closeLoc = tok.Start
 	} else {
 		if p.tok.Kind == token.EOF {
-			p.error(openLoc, openLoc, "bracket is never closed (end of file reached)")
+			p.error(openLoc, openLoc, report.CodeUnclosedBracket, "bracket is never closed (end of file reached)")
 		} else {
 			start, end := p.skipTo()
 			p.errorExpectedToken(start, end, append(separators, closing)...)
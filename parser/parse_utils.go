@@ -18,23 +18,38 @@ func setAttributes(node ast.Decl, attrs *ast.AttributeList) {
 	case *ast.StructDecl:
 		n.Attrs = attrs
 
+	case *ast.EnumDecl:
+		n.Attrs = attrs
+
 	case *ast.VarDecl:
 		n.Attrs = attrs
+
+	case *ast.ConstDecl:
+		n.Attrs = attrs
 	}
 }
 
-// func setDoc(node ast.Decl, commentGroup *ast.CommentGroup) {
-// 	switch n := node.(type) {
-// 	case *ast.ModuleDecl:
-// 		n.CommentGroup = commentGroup
+func setDoc(node ast.Decl, commentGroup *ast.CommentGroup) {
+	switch n := node.(type) {
+	case *ast.ModuleDecl:
+		n.CommentGroup = commentGroup
 
-// 	case *ast.TypeAliasDecl:
-// 		n.CommentGroup = commentGroup
+	case *ast.TypeAliasDecl:
+		n.CommentGroup = commentGroup
 
-// 	case *ast.FuncDecl:
-// 		n.CommentGroup = commentGroup
+	case *ast.FuncDecl:
+		n.CommentGroup = commentGroup
 
-// 	case *ast.VarDecl:
-// 		n.CommentGroup = commentGroup
-// 	}
-// }
+	case *ast.VarDecl:
+		n.CommentGroup = commentGroup
+
+	case *ast.ConstDecl:
+		n.CommentGroup = commentGroup
+
+	case *ast.StructDecl:
+		n.CommentGroup = commentGroup
+
+	case *ast.EnumDecl:
+		n.CommentGroup = commentGroup
+	}
+}
@@ -22,7 +22,7 @@ type Parser struct {
 
 	// State
 
-	// commentGroup *ast.CommentGroup
+	commentGroup *ast.CommentGroup // Doc comments before the current token.
 	restoreData  []restoreData
 }
 
@@ -37,12 +37,14 @@ const (
 )
 
 func New(cfg *config.Config, tokens []token.Token, flags Flags) *Parser {
-	return &Parser{
-		config: cfg,
-		tokens: tokens,
-		flags:  flags,
-		tok:    tokens[0],
+	p := &Parser{
+		config:  cfg,
+		tokens:  tokens,
+		flags:   flags,
+		current: -1,
 	}
+	p.next()
+	return p
 }
 
 func (p *Parser) Errors() []error {
@@ -55,15 +57,66 @@ func Parse(cfg *config.Config, tokens []token.Token, flags Flags) (*ast.List, []
 	return stmts, p.Errors()
 }
 
-func ParseExpr(cfg *config.Config, input []byte) (ast.Node, []error) {
-	toks, errors := scanner.Scan(input, 0, scanner.DefaultFlags)
+// Parses the token stream that contains comments. Comments are removed
+// from the stream before parsing and returned in the source order.
+// Comments on adjacent lines are grouped together.
+func ParseWithComments(cfg *config.Config, tokens []token.Token, flags Flags) (*ast.List, []*ast.CommentGroup, []error) {
+	groups := []*ast.CommentGroup{}
+	stream := make([]token.Token, 0, len(tokens))
+
+	for _, tok := range tokens {
+		if tok.Kind != token.Comment {
+			stream = append(stream, tok)
+			continue
+		}
+
+		comment := &ast.Comment{
+			Data:  tok.Data[1:],
+			Start: tok.Start,
+			End:   tok.End,
+		}
+
+		if len(groups) > 0 {
+			last := groups[len(groups)-1]
+
+			if last.LocEnd().Line+1 == comment.Start.Line && stream[len(stream)-1].Kind == token.NewLine {
+				last.Comments = append(last.Comments, comment)
+				continue
+			}
+		}
+
+		groups = append(groups, &ast.CommentGroup{Comments: []*ast.Comment{comment}})
+	}
+
+	list, errs := Parse(cfg, stream, flags)
+	return list, groups, errs
+}
+
+// Parses the input as a single expression. Only new lines and
+// semicolons are allowed after the expression.
+func ParseExpr(cfg *config.Config, fileID config.FileID, input []byte) (ast.Node, []error) {
+	toks, errors := scanner.Scan(input, fileID, scanner.DefaultFlags)
 
 	if len(errors) > 0 {
 		return nil, errors
 	}
 
-	p := New(cfg, toks, DefaultFlags|Trace)
+	flags := DefaultFlags
+	if cfg.TraceParser {
+		flags |= Trace
+	}
+
+	p := New(cfg, toks, flags)
 	expr := p.parseExpr()
+
+	for p.tok.Kind == token.NewLine || p.tok.Kind == token.Semicolon {
+		p.next()
+	}
+
+	if p.tok.Kind != token.EOF {
+		p.errorExpected(p.tok.Start, p.tok.End, "end of the expression")
+	}
+
 	return expr, p.Errors()
 }
 
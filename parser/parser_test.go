@@ -1,18 +1,23 @@
 package parser
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
+	"flag"
 	"os"
 	"slices"
 	"testing"
 
+	"github.com/saffage/jet/ast"
 	"github.com/saffage/jet/config"
 	"github.com/saffage/jet/scanner"
 )
 
 var cfg *config.Config
 
+var update = flag.Bool("update", false, "update the golden files in 'testdata'")
+
 // TODO more tests
 
 func TestMain(m *testing.M) {
@@ -77,6 +82,33 @@ func TestExprs(t *testing.T) {
 	}
 }
 
+func TestParseExpr(t *testing.T) {
+	t.Cleanup(cleanup)
+
+	cases := []struct {
+		input  string
+		errors []error
+	}{
+		{input: "1 + 2"},
+		{input: "f(x)\n"},
+		{input: "{ 1 }"},
+		{input: "1 2", errors: []error{errors.New("expected end of the expression, found untyped int")}},
+		{input: "x = 1"},
+		{input: "a; b", errors: []error{errors.New("expected end of the expression, found identifier")}},
+	}
+
+	for _, c := range cases {
+		expr, errs := ParseExpr(cfg, 1, []byte(c.input))
+		if !checkErrors(t, errs, c.errors) {
+			continue
+		}
+
+		if expr == nil {
+			t.Errorf("expected an expression for %q", c.input)
+		}
+	}
+}
+
 func test(t *testing.T, c testCase) {
 	tokens, errs := scanner.Scan(([]byte)(c.input), 1, c.scannerFlags)
 
@@ -85,41 +117,67 @@ func test(t *testing.T, c testCase) {
 	}
 
 	t.Run(c.name, func(t *testing.T) {
-		ast, errs := Parse(cfg, tokens, c.parserFlags)
+		list, errs := Parse(cfg, tokens, c.parserFlags)
 		if !checkErrors(t, errs, c.errors) {
 			return
 		}
 
-		if c.expectedJSON != "" {
-			filename := "./testdata/" + c.expectedJSON
-			expect, err := os.ReadFile(filename)
-			if err != nil {
-				t.Errorf("unexpected error while reading file '%s': %s", filename, err)
-				return
-			}
+		actual, err := marshalIndent(list)
+		if err != nil {
+			t.Error("unexpected JSON marshal error:", err)
+			return
+		}
 
-			actual, err := json.MarshalIndent(ast, "", "    ")
-			if err != nil {
-				t.Error("unexpected JSON marshal error:", err)
-				return
-			}
+		if c.expectedJSON == "" {
+			t.Logf("no AST was expected\ngot %s", string(actual))
+			return
+		}
 
-			if slices.Compare(actual, expect) != 0 {
-				t.Errorf("unexpected AST was parsed\nexpect %s\nactual %s", string(expect), string(actual))
-				return
-			}
-		} else {
-			encoded, err := json.MarshalIndent(ast, "", "    ")
-			if err != nil {
-				t.Error("unexpected JSON marshal error:", err)
-				return
+		filename := "./testdata/" + c.expectedJSON
+
+		if *update {
+			if err := os.WriteFile(filename, actual, 0o644); err != nil {
+				t.Error(err)
 			}
 
-			t.Logf("no AST was expected\ngot %s", string(encoded))
+			return
+		}
+
+		expect, err := os.ReadFile(filename)
+		if err != nil {
+			t.Errorf("unexpected error while reading file '%s': %s", filename, err)
+			return
+		}
+
+		if slices.Compare(actual, expect) != 0 {
+			t.Errorf("unexpected AST was parsed\nexpect %s\nactual %s", string(expect), string(actual))
+			return
+		}
+
+		// The golden file must be decoded to the same tree.
+		decoded, err := ast.UnmarshalNode(expect)
+		if err != nil {
+			t.Error("unexpected JSON unmarshal error:", err)
+			return
+		}
+
+		if encoded, _ := marshalIndent(decoded); slices.Compare(encoded, expect) != 0 {
+			t.Errorf("decoded AST differs from the golden file\nexpect %s\nactual %s", string(expect), string(encoded))
 		}
 	})
 }
 
+func marshalIndent(node ast.Node) ([]byte, error) {
+	encoded, err := ast.MarshalNode(node)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bytes.Buffer{}
+	err = json.Indent(&buf, encoded, "", "    ")
+	return buf.Bytes(), err
+}
+
 func checkErrors(t *testing.T, gotErrors, wantErrors []error) bool {
 	maxIndex := max(len(gotErrors), len(wantErrors))
 
@@ -158,3 +216,384 @@ func checkErrors(t *testing.T, gotErrors, wantErrors []error) bool {
 
 	return true
 }
+
+func TestJSONRoundTrip(t *testing.T) {
+	t.Cleanup(cleanup)
+
+	const source = `import Other
+
+## Shape kind.
+enum Kind {
+    ## Circle.
+    Circle
+    Square
+}
+
+struct Point {
+    ## X coordinate.
+    x i32
+    y i32
+}
+
+alias Points = [4]Point
+
+const N = 4
+
+@(ExternC) func abs(x i32) i32
+
+func f(p *Point, points Points) i32 {
+    var i = 0
+    while i < N {
+        if points[i].x == -p.y {
+            break
+        } else if !(i > 2) {
+            i += 1
+            continue
+        } else {
+            @print(i)
+        }
+    }
+    (i, 'str', 1.5)
+    points[0].x
+}
+`
+	tokens, errs := scanner.Scan([]byte(source), 1, scanner.SkipWhitespace)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected scanner errors: %v", errs)
+	}
+
+	list, errs := Parse(cfg, tokens, DefaultFlags)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	encoded, err := ast.MarshalNode(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := ast.UnmarshalNode(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.String() != list.String() {
+		t.Errorf("decoded AST differs from the source\nexpect %s\nactual %s", list, decoded)
+	}
+
+	if reencoded, _ := ast.MarshalNode(decoded); slices.Compare(reencoded, encoded) != 0 {
+		t.Errorf("decoded AST is encoded differently\nexpect %s\nactual %s", encoded, reencoded)
+	}
+
+	enum := decoded.(*ast.List).Nodes[1].(*ast.EnumDecl)
+	if doc := enum.MemberDoc(enum.Body.Nodes[0].(*ast.Ident)); doc != "Circle." {
+		t.Errorf("unexpected doc of the enum member: %q", doc)
+	}
+
+	if _, err := ast.UnmarshalNode([]byte(`{"Node":"Call","X":{"Node":"Ident"},"Args":{"Node":"Ident"}}`)); err == nil {
+		t.Error("expected an error for the node of the wrong type")
+	}
+}
+
+func TestRecovery(t *testing.T) {
+	t.Cleanup(cleanup)
+
+	const source = `func (x) {
+    var a = 1
+}
+
+const C = )
+
+struct P {
+    x i32
+}
+
+func f() {
+    var v = ok(
+}
+`
+	tokens, errs := scanner.Scan([]byte(source), 1, scanner.SkipWhitespace)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected scanner errors: %v", errs)
+	}
+
+	list, errs := Parse(cfg, tokens, DefaultFlags)
+	checkErrors(t, errs, []error{
+		errors.New("expected identifier, found '('"),
+		errors.New("expected operand, found ')'"),
+		errors.New("expected operand, found '}'"),
+		errors.New("expected body, found end of file"),
+	})
+
+	if list == nil {
+		t.Fatal("expected a partial tree")
+	}
+
+	kinds := []string{}
+
+	for _, node := range list.Nodes {
+		switch node.(type) {
+		case *ast.BadNode:
+			kinds = append(kinds, "bad")
+
+		case *ast.StructDecl:
+			kinds = append(kinds, "struct")
+
+		default:
+			kinds = append(kinds, "other")
+		}
+	}
+
+	if want := []string{"bad", "bad", "struct", "bad"}; !slices.Equal(kinds, want) {
+		t.Errorf("unexpected nodes: %v, expected %v", kinds, want)
+	}
+}
+
+// The unclosed bracket must not hide the declarations after it.
+func TestRecoveryUnclosedBracket(t *testing.T) {
+	t.Cleanup(cleanup)
+
+	const source = `func g( {
+ 1
+}
+
+func main() {
+ undefined1
+}
+func k() {
+ undefined2
+}
+`
+	tokens, errs := scanner.Scan([]byte(source), 1, scanner.SkipWhitespace)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected scanner errors: %v", errs)
+	}
+
+	list, errs := Parse(cfg, tokens, DefaultFlags)
+	if len(errs) == 0 {
+		t.Fatal("expected errors")
+	}
+
+	if list == nil {
+		t.Fatal("expected a partial tree")
+	}
+
+	funcs := []string{}
+
+	for _, node := range list.Nodes {
+		if decl, _ := node.(*ast.FuncDecl); decl != nil {
+			funcs = append(funcs, decl.Name.Name)
+		}
+	}
+
+	if want := []string{"main", "k"}; !slices.Equal(funcs, want) {
+		t.Errorf("unexpected functions: %v, expected %v: %v", funcs, want, errs)
+	}
+}
+
+func TestReturn(t *testing.T) {
+	t.Cleanup(cleanup)
+
+	const source = `func f() {
+    if true { return }
+    return
+}
+
+func g() i32 {
+    return 1 + 2
+}
+`
+	tokens, errs := scanner.Scan([]byte(source), 1, scanner.SkipWhitespace)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected scanner errors: %v", errs)
+	}
+
+	list, errs := Parse(cfg, tokens, DefaultFlags)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	returns := []string{}
+	visit := ast.Visitor(nil)
+	visit = func(node ast.Node) ast.Visitor {
+		if ret, _ := node.(*ast.Return); ret != nil {
+			returns = append(returns, ret.String())
+		}
+		return visit
+	}
+
+	ast.WalkTopDown(visit, list)
+
+	if want := []string{"return", "return", "return (1 + 2)"}; !slices.Equal(returns, want) {
+		t.Errorf("unexpected returns: %q, expected %q", returns, want)
+	}
+}
+
+func TestLabeledWhile(t *testing.T) {
+	t.Cleanup(cleanup)
+
+	const source = `func f() {
+    outer: while a {
+        while b { continue outer }
+        break outer
+    }
+}
+`
+	tokens, errs := scanner.Scan([]byte(source), 1, scanner.SkipWhitespace)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected scanner errors: %v", errs)
+	}
+
+	list, errs := Parse(cfg, tokens, DefaultFlags)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	labels := []string{}
+	visit := ast.Visitor(nil)
+	visit = func(node ast.Node) ast.Visitor {
+		switch node := node.(type) {
+		case *ast.While:
+			if node.Label != nil {
+				labels = append(labels, "while "+node.Label.Name)
+			}
+
+		case *ast.Break:
+			labels = append(labels, node.String())
+
+		case *ast.Continue:
+			labels = append(labels, node.String())
+		}
+		return visit
+	}
+
+	ast.WalkTopDown(visit, list)
+
+	if want := []string{"while outer", "continue outer", "break outer"}; !slices.Equal(labels, want) {
+		t.Errorf("unexpected labels: %q, expected %q", labels, want)
+	}
+
+	tokens, _ = scanner.Scan([]byte("func f() { outer: if a {} }"), 1, scanner.SkipWhitespace)
+
+	if _, errs := Parse(cfg, tokens, DefaultFlags); len(errs) == 0 {
+		t.Errorf("expected an error for the label of 'if'")
+	}
+}
+
+func TestFor(t *testing.T) {
+	t.Cleanup(cleanup)
+
+	const source = `func f() {
+    for i in 0..<n - 1 {}
+    for i, x in arr {}
+    outer: for x in 1..10 { continue outer }
+}
+`
+	tokens, errs := scanner.Scan([]byte(source), 1, scanner.SkipWhitespace)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected scanner errors: %v", errs)
+	}
+
+	list, errs := Parse(cfg, tokens, DefaultFlags)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	loops := []string{}
+	visit := ast.Visitor(nil)
+	visit = func(node ast.Node) ast.Visitor {
+		if loop, _ := node.(*ast.For); loop != nil {
+			loops = append(loops, loop.String())
+		}
+		return visit
+	}
+
+	ast.WalkTopDown(visit, list)
+
+	want := []string{
+		"for i in (0 ..< (n - 1)) {}",
+		"for i, x in arr {}",
+		"outer: for x in (1 .. 10) { continue outer }",
+	}
+
+	if !slices.Equal(loops, want) {
+		t.Errorf("unexpected loops: %q, expected %q", loops, want)
+	}
+}
+
+func TestVal(t *testing.T) {
+	t.Cleanup(cleanup)
+
+	const source = `val x i32 = 1
+var y = 2
+`
+	tokens, errs := scanner.Scan([]byte(source), 1, scanner.SkipWhitespace)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected scanner errors: %v", errs)
+	}
+
+	list, errs := Parse(cfg, tokens, DefaultFlags)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	decls := []string{}
+
+	for _, node := range list.Nodes {
+		if decl, _ := node.(*ast.VarDecl); decl != nil {
+			decls = append(decls, decl.String())
+		}
+	}
+
+	want := []string{"val x i32 = 1", "var y = 2"}
+
+	if !slices.Equal(decls, want) {
+		t.Errorf("unexpected declarations: %q, expected %q", decls, want)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	t.Cleanup(cleanup)
+
+	const source = `func f() {
+    match c {
+        Color.Red, Color.Green => x = 1
+        Color.Blue => return
+        _ => {}
+    }
+    match n { 0 => a(); 1 + 1 => b() }
+}
+`
+	tokens, errs := scanner.Scan([]byte(source), 1, scanner.SkipWhitespace)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected scanner errors: %v", errs)
+	}
+
+	list, errs := Parse(cfg, tokens, DefaultFlags)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	arms := []string{}
+	visit := ast.Visitor(nil)
+	visit = func(node ast.Node) ast.Visitor {
+		if arm, _ := node.(*ast.MatchArm); arm != nil {
+			arms = append(arms, arm.String())
+		}
+		return visit
+	}
+
+	ast.WalkTopDown(visit, list)
+
+	want := []string{
+		"Color.Red, Color.Green => (x = 1)",
+		"Color.Blue => return",
+		"_ => {}",
+		"0 => a()",
+		"(1 + 1) => b()",
+	}
+
+	if !slices.Equal(arms, want) {
+		t.Errorf("unexpected arms: %q, expected %q", arms, want)
+	}
+}
@@ -8,6 +8,7 @@ import (
 )
 
 type Error struct {
+	Code       report.Code
 	Message    string
 	Details    string
 	Start, End token.Loc
@@ -22,12 +23,24 @@ func (e Error) Error() string {
 }
 
 func (e Error) Report() {
-	report.TaggedErrorAt("scanner", e.Start, e.End, e.Message)
+	report.Emit(e.Diagnostic())
+}
+
+func (e Error) Diagnostic() *report.Diagnostic {
+	return &report.Diagnostic{
+		Kind:    report.KindError,
+		Tag:     "scanner",
+		Code:    e.Code,
+		Message: e.Message,
+		Start:   e.Start,
+		End:     e.End,
+	}
 }
 
 // Emits an error. Error end is a current scanner position.
-func (s *Scanner) error(message string, start token.Loc, details ...any) {
+func (s *Scanner) error(code report.Code, message string, start token.Loc, details ...any) {
 	s.errors = append(s.errors, Error{
+		Code:    code,
 		Message: message,
 		Details: fmt.Sprint(details...),
 		Start:   start,
@@ -35,12 +48,12 @@ func (s *Scanner) error(message string, start token.Loc, details ...any) {
 	})
 }
 
-func (s *Scanner) errorExpected(message string, pos token.Loc, details ...any) {
+func (s *Scanner) errorExpected(code report.Code, message string, pos token.Loc, details ...any) {
 	message = "expected " + message
-	s.error(message, pos, details...)
+	s.error(code, message, pos, details...)
 }
 
-func (s *Scanner) errorUnexpected(message string, pos token.Loc, details ...any) {
+func (s *Scanner) errorUnexpected(code report.Code, message string, pos token.Loc, details ...any) {
 	message = "unexpected " + message
-	s.error(message, pos, details...)
+	s.error(code, message, pos, details...)
 }
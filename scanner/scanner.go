@@ -7,6 +7,7 @@ import (
 
 	"github.com/galsondor/go-ascii"
 	"github.com/saffage/jet/config"
+	"github.com/saffage/jet/internal/report"
 	"github.com/saffage/jet/scanner/base"
 	"github.com/saffage/jet/token"
 )
@@ -65,6 +66,8 @@ func (s *Scanner) Next() token.Token {
 
 		switch {
 		case s.Match('#'):
+			// NOTE: this also covers the shebang line ('#!...'),
+			// so Jet files can be used as scripts.
 			tok = token.Token{
 				Kind: token.Comment,
 				Data: s.TakeUntil(isNewLineChar),
@@ -201,7 +204,7 @@ func (s *Scanner) Next() token.Token {
 			tok = token.Token{Kind: kind}
 
 		default:
-			s.error("illegal character", s.Pos())
+			s.error(report.CodeIllegalCharacter, "illegal character", s.Pos())
 
 			tok = token.Token{
 				Kind: token.Illegal,
@@ -278,7 +281,7 @@ func (s *Scanner) scanString() token.Token {
 				}
 
 			default:
-				s.errorUnexpected(fmt.Sprintf("character escape `\\%c`", s.Prev()), s.PrevPos())
+				s.errorUnexpected(report.CodeInvalidEscape, fmt.Sprintf("character escape `\\%c`", s.Prev()), s.PrevPos())
 				data = []byte{'\\', s.Prev()}
 			}
 		} else {
@@ -289,7 +292,7 @@ func (s *Scanner) scanString() token.Token {
 	})
 
 	if !s.Consume(quote) {
-		s.error("unterminated string literal", quotePos)
+		s.error(report.CodeUnterminatedString, "unterminated string literal", quotePos)
 		return token.Token{
 			Kind:  token.Illegal,
 			Data:  data,
@@ -315,7 +318,7 @@ func (s *Scanner) parseBytes(n int) ([]byte, bool) {
 			realBytes = append(realBytes, '_')
 
 			if !ascii.IsHexDigit(s.Peek()) {
-				s.error("invalid byte", s.Pos())
+				s.error(report.CodeInvalidEscape, "invalid byte", s.Pos())
 				return realBytes, false
 			}
 		}
@@ -331,17 +334,17 @@ func (s *Scanner) parseBytes(n int) ([]byte, bool) {
 	}
 
 	if i == 0 {
-		s.error("invalid byte", startPos)
+		s.error(report.CodeInvalidEscape, "invalid byte", startPos)
 		return realBytes, false
 	} else if i < n {
-		s.error(fmt.Sprintf("invalid byte (expected %d bytes)", n), s.Pos())
+		s.error(report.CodeInvalidEscape, fmt.Sprintf("invalid byte (expected %d bytes)", n), s.Pos())
 		return realBytes, false
 	}
 
 	result := make([]byte, n/2)
 
 	if _, err := hex.Decode(result, bytes); err != nil {
-		s.error(err.Error(), s.Pos())
+		s.error(report.CodeInvalidEscape, err.Error(), s.Pos())
 		return nil, false
 	}
 
@@ -369,14 +372,14 @@ func (s *Scanner) scanNumber() token.Token {
 			return num
 
 		case s.Match('X', 'B', 'O'):
-			s.error("uppercase letters is not allowed, use lowercase instead", s.Pos())
+			s.error(report.CodeInvalidNumber, "uppercase letters is not allowed, use lowercase instead", s.Pos())
 			return token.Token{
 				Kind: token.Illegal,
 				Data: string(s.Peek()),
 			}
 
 		case ascii.IsDigit(s.Peek()):
-			s.error("`0` as the first character of a number literal is not allowed", s.Pos())
+			s.error(report.CodeInvalidNumber, "`0` as the first character of a number literal is not allowed", s.Pos())
 			return token.Token{
 				Kind: token.Illegal,
 				Data: string(s.Prev()),
@@ -419,7 +422,7 @@ func (s *Scanner) scanNumber() token.Token {
 	}
 
 	if token.IsIdentifierStartChar(s.Peek()) {
-		s.errorUnexpected("character", s.Pos(), "numeric literals have no suffixes")
+		s.errorUnexpected(report.CodeInvalidNumber, "character", s.Pos(), "numeric literals have no suffixes")
 		return token.Token{
 			Kind: token.Illegal,
 			Data: string(s.Peek()),
@@ -446,7 +449,7 @@ func (s *Scanner) scanNumber() token.Token {
 // Pattern is: `<number> ('_' <number>)*`
 func (s *Scanner) parseNumber(predicate func(byte) bool, expected string) token.Token {
 	if !predicate(s.Peek()) {
-		s.errorExpected(expected, s.Pos())
+		s.errorExpected(report.CodeInvalidNumber, expected, s.Pos())
 		return token.Token{
 			Kind: token.Illegal,
 			Data: string(s.Peek()),
@@ -462,7 +465,7 @@ func (s *Scanner) parseNumber(predicate func(byte) bool, expected string) token.
 
 			if !predicate(s.Peek()) {
 				num.WriteByte(s.Peek())
-				s.errorExpected(expected+" after `_`", s.Pos())
+				s.errorExpected(report.CodeInvalidNumber, expected+" after `_`", s.Pos())
 				return token.Token{
 					Kind: token.Illegal,
 					Data: num.String(),
@@ -27,3 +27,7 @@ func TestSelectToken(t *testing.T) {
 	testTokenKinds(t, "...", token.Ellipsis, token.EOF)
 	testTokenKinds(t, "..<", token.Dot2Less, token.EOF)
 }
+
+func TestShebang(t *testing.T) {
+	testTokenKinds(t, "#!/usr/bin/env jet run\nfoo", token.Comment, token.NewLine, token.Ident, token.EOF)
+}
@@ -5,16 +5,21 @@ import (
 	"fmt"
 )
 
+// Decodes the kind from its name, e.g. "LParen".
 func (kind *Kind) UnmarshalJSON(data []byte) error {
-	name := string(data)
-	k := KindFromString(name)
+	name := ""
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
 
-	if k == Illegal {
-		return fmt.Errorf("invalid kind: '%s'", name)
+	for _, k := range AllKinds() {
+		if k.String() == name {
+			*kind = k
+			return nil
+		}
 	}
 
-	*kind = k
-	return nil
+	return fmt.Errorf("invalid kind: '%s'", name)
 }
 
 func (kind Kind) MarshalJSON() ([]byte, error) {
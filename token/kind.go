@@ -91,6 +91,9 @@ const (
 	KwIf       // keyword 'if'
 	KwElse     // keyword 'else'
 	KwWhile    // keyword 'while'
+	KwFor      // keyword 'for'
+	KwIn       // keyword 'in'
+	KwMatch    // keyword 'match'
 	KwReturn   // keyword 'return'
 	KwBreak    // keyword 'break'
 	KwContinue // keyword 'continue'
@@ -245,6 +248,9 @@ var representableKinds = map[Kind]string{
 	KwIf:            "if",
 	KwElse:          "else",
 	KwWhile:         "while",
+	KwFor:           "for",
+	KwIn:            "in",
+	KwMatch:         "match",
 	KwReturn:        "return",
 	KwBreak:         "break",
 	KwContinue:      "continue",
@@ -74,14 +74,17 @@ func _() {
 	_ = x[KwIf-63]
 	_ = x[KwElse-64]
 	_ = x[KwWhile-65]
-	_ = x[KwReturn-66]
-	_ = x[KwBreak-67]
-	_ = x[KwContinue-68]
+	_ = x[KwFor-66]
+	_ = x[KwIn-67]
+	_ = x[KwMatch-68]
+	_ = x[KwReturn-69]
+	_ = x[KwBreak-70]
+	_ = x[KwContinue-71]
 }
 
-const _Kind_name = "IllegalEOFCommentWhitespaceTabNewLineIdentIntFloatStringLParenRParenLCurlyRCurlyLBracketRBracketCommaColonSemicolonEqEqOpBangNeOpLtOpLeOpGtOpGeOpPlusPlusEqMinusMinusEqAsteriskAsteriskEqSlashSlashEqPercentPercentEqAmpPipeCaretAtQuestionMarkQuestionMarkDotArrowFatArrowShlShrDotDot2Dot2LessEllipsisKwAndKwOrKwModuleKwImportKwAliasKwStructKwEnumKwFuncKwValKwVarKwConstKwOfKwIfKwElseKwWhileKwReturnKwBreakKwContinue"
+const _Kind_name = "IllegalEOFCommentWhitespaceTabNewLineIdentIntFloatStringLParenRParenLCurlyRCurlyLBracketRBracketCommaColonSemicolonEqEqOpBangNeOpLtOpLeOpGtOpGeOpPlusPlusEqMinusMinusEqAsteriskAsteriskEqSlashSlashEqPercentPercentEqAmpPipeCaretAtQuestionMarkQuestionMarkDotArrowFatArrowShlShrDotDot2Dot2LessEllipsisKwAndKwOrKwModuleKwImportKwAliasKwStructKwEnumKwFuncKwValKwVarKwConstKwOfKwIfKwElseKwWhileKwForKwInKwMatchKwReturnKwBreakKwContinue"
 
-var _Kind_index = [...]uint16{0, 7, 10, 17, 27, 30, 37, 42, 45, 50, 56, 62, 68, 74, 80, 88, 96, 101, 106, 115, 117, 121, 125, 129, 133, 137, 141, 145, 149, 155, 160, 167, 175, 185, 190, 197, 204, 213, 216, 220, 225, 227, 239, 254, 259, 267, 270, 273, 276, 280, 288, 296, 301, 305, 313, 321, 328, 336, 342, 348, 353, 358, 365, 369, 373, 379, 386, 394, 401, 411}
+var _Kind_index = [...]uint16{0, 7, 10, 17, 27, 30, 37, 42, 45, 50, 56, 62, 68, 74, 80, 88, 96, 101, 106, 115, 117, 121, 125, 129, 133, 137, 141, 145, 149, 155, 160, 167, 175, 185, 190, 197, 204, 213, 216, 220, 225, 227, 239, 254, 259, 267, 270, 273, 276, 280, 288, 296, 301, 305, 313, 321, 328, 336, 342, 348, 353, 358, 365, 369, 373, 379, 386, 391, 395, 402, 410, 417, 427}
 
 func (i Kind) String() string {
 	if i >= Kind(len(_Kind_index)-1) {
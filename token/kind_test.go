@@ -35,3 +35,26 @@ func TestKindRepr(t *testing.T) {
 		t.Errorf("\nextra token representations: %v", extraKinds)
 	}
 }
+
+func TestKindJSON(t *testing.T) {
+	for _, kind := range AllKinds() {
+		data, err := kind.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decoded := Illegal
+		if err := decoded.UnmarshalJSON(data); err != nil {
+			t.Fatal(err)
+		}
+
+		if decoded != kind {
+			t.Errorf("expected %s, got %s", kind, decoded)
+		}
+	}
+
+	kind := Illegal
+	if err := kind.UnmarshalJSON([]byte(`"NotAKind"`)); err == nil {
+		t.Error("expected an error for an invalid kind")
+	}
+}
@@ -15,22 +15,16 @@ type Loc struct {
 	Char   uint32
 }
 
-// Uses [config.Global] to get the file info.
+// The file path is not included, since the file table belongs to the
+// compilation session (see [config.Config]).
 //
 // Return string in one of this formats depending on location data:
-//   - "file"
-//   - "file:line"
-//   - "file:line:column"
 //   - "line"
 //   - "line:column"
 //   - "???"
 func (l Loc) String() string {
 	s := strings.Builder{}
 
-	if fileinfo, present := config.Global.Files[l.FileID]; present && fileinfo.Path != "" {
-		s.WriteString(fileinfo.Path + ":")
-	}
-
 	if l.Line > 0 {
 		s.WriteString(fmt.Sprintf("%d", l.Line))
 
@@ -23,6 +23,9 @@ func (a *Alias) Equals(other Type) bool { return a.actual.Equals(removeAlias(oth
 
 func (a *Alias) Underlying() Type { return a.actual.Underlying() }
 
+// Returns the type the alias was declared with (can be an alias).
+func (a *Alias) Base() Type { return a.base }
+
 func (a *Alias) String() string {
 	if IsPrimitive(a.base) {
 		return a.name
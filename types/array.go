@@ -15,6 +15,9 @@ func NewArray(size int, t Type) *Array {
 }
 
 func (t *Array) Equals(other Type) bool {
+	if IsInvalid(other) {
+		return true
+	}
 	if t2 := AsPrimitive(other); t2 != nil {
 		return t2.kind == KindAny
 	}
@@ -11,6 +11,9 @@ func NewEnum(fields ...string) *Enum {
 }
 
 func (t *Enum) Equals(other Type) bool {
+	if IsInvalid(other) {
+		return true
+	}
 	if t2 := AsPrimitive(other); t2 != nil {
 		return t2.kind == KindAny
 	}
@@ -27,6 +27,9 @@ func NewFunc(result *Tuple, params *Tuple, variadic bool) *Func {
 }
 
 func (t *Func) Equals(other Type) bool {
+	if IsInvalid(other) {
+		return true
+	}
 	if t2 := AsPrimitive(other); t2 != nil {
 		return t2.kind == KindAny
 	}
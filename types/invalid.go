@@ -0,0 +1,18 @@
+package types
+
+// Type of the expression that contains an error. It is equal to any
+// other type, so the error is reported only once and doesn't cause
+// the other errors.
+type invalid struct{}
+
+var Invalid = &invalid{}
+
+func (t *invalid) Equals(other Type) bool { return true }
+
+func (t *invalid) Underlying() Type { return t }
+
+func (t *invalid) String() string { return "invalid" }
+
+func IsInvalid(t Type) bool {
+	return t != nil && t.Underlying() == Invalid
+}
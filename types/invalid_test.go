@@ -0,0 +1,37 @@
+package types
+
+import "testing"
+
+func TestInvalidEquals(t *testing.T) {
+	for _, other := range []Type{
+		I32,
+		UntypedInt,
+		String,
+		Unit,
+		NewTuple(I32, Bool),
+		NewRef(U8),
+		NewArray(2, F32),
+		NewEnum("A", "B"),
+		NewFunc(nil, nil, false),
+		NewTypeDesc(I32),
+		NewAlias(I64, "Int"),
+	} {
+		if !Invalid.Equals(other) {
+			t.Errorf("invalid type must be equal to (%s)", other)
+		}
+
+		if !other.Equals(Invalid) {
+			t.Errorf("type (%s) must be equal to the invalid type", other)
+		}
+	}
+}
+
+func TestIsInvalid(t *testing.T) {
+	if !IsInvalid(Invalid) || !IsInvalid(NewTuple(Invalid)) || !IsInvalid(NewAlias(Invalid, "T")) {
+		t.Error("expected invalid type")
+	}
+
+	if IsInvalid(nil) || IsInvalid(I32) || IsInvalid(NewTuple(Invalid, I32)) {
+		t.Error("unexpected invalid type")
+	}
+}
@@ -105,6 +105,9 @@ func (t *Primitive) IsImplicitlyConvertibleTo(target Type) bool {
 				panic("unreachable")
 			}
 
+		case *invalid:
+			return true
+
 		case *Struct:
 			if target == String {
 				return t.kind == KindUntypedString
@@ -163,6 +166,24 @@ func SkipUntyped(t Type) Type {
 	return t
 }
 
+func IsInteger(t Type) bool {
+	if t := AsPrimitive(t); t != nil {
+		switch t.kind {
+		case KindUntypedInt,
+			KindI8,
+			KindI16,
+			KindI32,
+			KindI64,
+			KindU8,
+			KindU16,
+			KindU32,
+			KindU64:
+			return true
+		}
+	}
+	return false
+}
+
 func IsUntyped(t Type) bool {
 	if t != nil {
 		switch t := t.Underlying().(type) {
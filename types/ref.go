@@ -12,6 +12,9 @@ func NewRef(t Type) *Ref {
 }
 
 func (t *Ref) Equals(target Type) bool {
+	if IsInvalid(target) {
+		return true
+	}
 	if t2 := AsPrimitive(target); t2 != nil {
 		return t2.kind == KindPointer || t2.kind == KindAny
 	}
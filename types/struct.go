@@ -32,6 +32,9 @@ func NewStruct(fields ...StructField) *Struct {
 }
 
 func (t *Struct) Equals(other Type) bool {
+	if IsInvalid(other) {
+		return true
+	}
 	if t2 := AsPrimitive(other); t2 != nil {
 		return t2.kind == KindAny
 	}
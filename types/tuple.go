@@ -19,6 +19,9 @@ func NewTuple(types ...Type) *Tuple {
 //
 // NOTE: name of the elements are not required to be the same.
 func (t *Tuple) Equals(other Type) bool {
+	if IsInvalid(other) {
+		return true
+	}
 	if t2 := AsPrimitive(other); t2 != nil && t2.kind == KindAny {
 		return true
 	}
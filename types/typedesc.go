@@ -21,6 +21,9 @@ func NewTypeDesc(t Type) *TypeDesc {
 }
 
 func (t *TypeDesc) Equals(other Type) bool {
+	if IsInvalid(other) {
+		return true
+	}
 	if t2 := AsTypeDesc(other); t2 != nil {
 		return t.base.Equals(t2.base)
 	}